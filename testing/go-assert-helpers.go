@@ -0,0 +1,599 @@
+package testing
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// AssertContains is a helper to assert a slice contains an element
+func AssertContains[T comparable](t *testing.T, haystack []T, needle T) {
+	t.Helper()
+	if !slices.Contains(haystack, needle) {
+		failf(t, "AssertContains", haystack, needle, "Expected %v to contain %v", haystack, needle)
+	}
+}
+
+// AssertNotContains is a helper to assert a slice does not contain an element
+func AssertNotContains[T comparable](t *testing.T, haystack []T, needle T) {
+	t.Helper()
+	if slices.Contains(haystack, needle) {
+		failf(t, "AssertNotContains", haystack, needle, "Expected %v not to contain %v", haystack, needle)
+	}
+}
+
+// AssertChanged is a helper to assert get now returns something other than
+// before, a value captured before the operation under test
+func AssertChanged[T comparable](t *testing.T, before T, get func() T) {
+	t.Helper()
+	if after := get(); after == before {
+		failf(t, "AssertChanged", after, nil, "Expected value to change, still %v", after)
+	}
+}
+
+// AssertUnchanged is a helper to assert get still returns before, a value
+// captured before the operation under test
+func AssertUnchanged[T comparable](t *testing.T, before T, get func() T) {
+	t.Helper()
+	if after := get(); after != before {
+		failf(t, "AssertUnchanged", after, before, "Expected value to stay %v, changed to %v", before, after)
+	}
+}
+
+// AssertOneOf is a helper to assert a value equals one of several allowed
+// values, for results where more than one outcome is valid
+func AssertOneOf[T comparable](t *testing.T, got T, allowed ...T) {
+	t.Helper()
+	if !slices.Contains(allowed, got) {
+		failf(t, "AssertOneOf", got, allowed, "Got %v, want one of %v", got, allowed)
+	}
+}
+
+// AssertStringContains is a helper to assert a string contains a substring
+func AssertStringContains(t *testing.T, s, substr string) {
+	t.Helper()
+	if !strings.Contains(s, substr) {
+		failf(t, "AssertStringContains", s, substr, "Expected %q to contain %q", s, substr)
+	}
+}
+
+// AssertStringNotContains is a helper to assert a string does not contain a substring
+func AssertStringNotContains(t *testing.T, s, substr string) {
+	t.Helper()
+	if strings.Contains(s, substr) {
+		failf(t, "AssertStringNotContains", s, substr, "Expected %q not to contain %q", s, substr)
+	}
+}
+
+// AssertMatchesRegexp is a helper to assert a string matches a regular
+// expression. The pattern is unanchored, so use ^ and $ to match the whole
+// string.
+func AssertMatchesRegexp(t *testing.T, value, pattern string) {
+	t.Helper()
+	if !compileRegexp(t, pattern).MatchString(value) {
+		failf(t, "AssertMatchesRegexp", value, pattern, "Expected %q to match /%s/", value, pattern)
+	}
+}
+
+// AssertNotMatchesRegexp is a helper to assert a string does not match a
+// regular expression
+func AssertNotMatchesRegexp(t *testing.T, value, pattern string) {
+	t.Helper()
+	if compileRegexp(t, pattern).MatchString(value) {
+		failf(t, "AssertNotMatchesRegexp", value, pattern, "Expected %q not to match /%s/", value, pattern)
+	}
+}
+
+// compileRegexp compiles pattern, failing the test if it is invalid
+func compileRegexp(t *testing.T, pattern string) *regexp.Regexp {
+	t.Helper()
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		t.Fatalf("Invalid regular expression %q: %v", pattern, err)
+	}
+	return re
+}
+
+// maxPreviewElements limits how many elements failure messages print
+const maxPreviewElements = 5
+
+// AssertLen is a helper to assert a slice has the expected length
+func AssertLen[T any](t *testing.T, collection []T, want int) {
+	t.Helper()
+	if len(collection) != want {
+		failf(t, "AssertLen", len(collection), want, "Got length %d, want %d: %s", len(collection), want, previewSlice(collection))
+	}
+}
+
+// AssertMapLen is a helper to assert a map has the expected number of entries
+func AssertMapLen[K comparable, V any](t *testing.T, m map[K]V, want int) {
+	t.Helper()
+	if len(m) != want {
+		failf(t, "AssertMapLen", len(m), want, "Got length %d, want %d: %v", len(m), want, m)
+	}
+}
+
+// AssertMapHasKey is a helper to assert a map has an entry for key
+func AssertMapHasKey[K comparable, V any](t *testing.T, m map[K]V, key K) {
+	t.Helper()
+	if _, ok := m[key]; !ok {
+		failf(t, "AssertMapHasKey", nil, key, "Expected key %v in map, got keys %v", key, slices.Collect(maps.Keys(m)))
+	}
+}
+
+// AssertMapKeyEquals is a helper to assert a map has key set to want,
+// reporting whether the key was absent or held a different value
+func AssertMapKeyEquals[K, V comparable](t *testing.T, m map[K]V, key K, want V) {
+	t.Helper()
+
+	got, ok := m[key]
+	switch {
+	case !ok:
+		failf(t, "AssertMapKeyEquals", nil, want, "Key %v is absent, want %v", key, want)
+	case got != want:
+		failf(t, "AssertMapKeyEquals", got, want, "Key %v is %v, want %v", key, got, want)
+	}
+}
+
+// AssertMapEqual is a helper to assert two maps hold the same entries. It
+// reports missing keys, extra keys and differing values.
+func AssertMapEqual[K, V comparable](t *testing.T, got, want map[K]V) {
+	t.Helper()
+
+	var diffs []string
+	for k, w := range want {
+		g, ok := got[k]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("[%v]: missing, want %v", k, w))
+		case g != w:
+			diffs = append(diffs, fmt.Sprintf("[%v]: got %v, want %v", k, g, w))
+		}
+	}
+	for k, g := range got {
+		if _, ok := want[k]; !ok {
+			diffs = append(diffs, fmt.Sprintf("[%v]: got %v, want nothing", k, g))
+		}
+	}
+
+	if len(diffs) > 0 {
+		slices.Sort(diffs)
+		failf(t, "AssertMapEqual", got, want, "Maps differ (got length %d, want %d):\n\t%s", len(got), len(want), strings.Join(diffs, "\n\t"))
+	}
+}
+
+// AssertMapContains is a helper to assert a map contains every entry of
+// subset; other keys in got are ignored
+func AssertMapContains[K, V comparable](t *testing.T, got, subset map[K]V) {
+	t.Helper()
+
+	var diffs []string
+	for k, w := range subset {
+		g, ok := got[k]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("[%v]: missing, want %v", k, w))
+		case g != w:
+			diffs = append(diffs, fmt.Sprintf("[%v]: got %v, want %v", k, g, w))
+		}
+	}
+
+	if len(diffs) > 0 {
+		slices.Sort(diffs)
+		failf(t, "AssertMapContains", got, subset, "Map does not contain expected entries:\n\t%s", strings.Join(diffs, "\n\t"))
+	}
+}
+
+// AssertEmpty is a helper to assert a string, slice, map, array or channel
+// has length zero; nil counts as empty
+func AssertEmpty(t *testing.T, v any) {
+	t.Helper()
+
+	n, ok := lengthOf(v)
+	if !ok {
+		t.Fatalf("AssertEmpty does not support %T", v)
+	}
+	if n != 0 {
+		failf(t, "AssertEmpty", v, nil, "Expected empty, got %T of length %d: %v", v, n, v)
+	}
+}
+
+// AssertNotEmpty is a helper to assert a string, slice, map, array or
+// channel has at least one element
+func AssertNotEmpty(t *testing.T, v any) {
+	t.Helper()
+
+	n, ok := lengthOf(v)
+	if !ok {
+		t.Fatalf("AssertNotEmpty does not support %T", v)
+	}
+	if n == 0 {
+		failf(t, "AssertNotEmpty", v, nil, "Expected non-empty, got empty %T", v)
+	}
+}
+
+// lengthOf returns len(v) for the kinds len accepts; ok is false otherwise
+func lengthOf(v any) (n int, ok bool) {
+	if v == nil {
+		return 0, true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		return rv.Len(), true
+	}
+	return 0, false
+}
+
+// AssertImplements is a helper to assert v's dynamic type implements the
+// interface I, e.g. for values stored in an interface{} registry. It returns
+// v as an I.
+func AssertImplements[I any](t *testing.T, v any) I {
+	t.Helper()
+
+	iface := reflect.TypeFor[I]()
+	if iface.Kind() != reflect.Interface {
+		t.Fatalf("AssertImplements requires an interface type, got %v", iface)
+	}
+
+	impl, ok := v.(I)
+	if !ok {
+		failf(t, "AssertImplements", fmt.Sprintf("%T", v), iface, "Expected %T to implement %v", v, iface)
+	}
+	return impl
+}
+
+// previewSlice formats the first few elements of a slice
+func previewSlice[T any](s []T) string {
+	if len(s) <= maxPreviewElements {
+		return fmt.Sprintf("%v", s)
+	}
+	return fmt.Sprintf("%v ... (%d more)", s[:maxPreviewElements], len(s)-maxPreviewElements)
+}
+
+// AssertFieldsEqual is a helper to assert the named fields of a struct (or
+// pointer to one) hold the expected values, ignoring every other field such
+// as IDs and timestamps. Names may be dotted to reach nested structs, e.g.
+// "Address.City". Untyped numbers in expected are converted to the field's
+// numeric type, so 30 matches an int64 field.
+func AssertFieldsEqual(t *testing.T, got any, expected map[string]any) {
+	t.Helper()
+
+	root := reflect.ValueOf(got)
+	for root.Kind() == reflect.Pointer && !root.IsNil() {
+		root = root.Elem()
+	}
+	if root.Kind() != reflect.Struct {
+		t.Fatalf("AssertFieldsEqual requires a struct, got %T", got)
+	}
+
+	var diffs []string
+	for _, name := range slices.Sorted(maps.Keys(expected)) {
+		field, err := fieldByPath(root, name)
+		if err != nil {
+			t.Fatalf("AssertFieldsEqual: %v", err)
+		}
+
+		gotValue := field.Interface()
+		want := convertNumber(expected[name], field.Type())
+		if !reflect.DeepEqual(gotValue, want) {
+			diffs = append(diffs, fmt.Sprintf("%s: got %v, want %v", name, gotValue, expected[name]))
+		}
+	}
+
+	if len(diffs) > 0 {
+		failf(t, "AssertFieldsEqual", got, expected, "Fields of %T differ:\n\t%s", got, strings.Join(diffs, "\n\t"))
+	}
+}
+
+// fieldByPath follows a dotted field path from a struct value, dereferencing
+// pointers along the way
+func fieldByPath(v reflect.Value, path string) (reflect.Value, error) {
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, fmt.Errorf("field %s: nil pointer before %s", path, name)
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("field %s: %s is not a struct", path, v.Type())
+		}
+
+		sf, ok := v.Type().FieldByName(name)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("field %s: %s has no field %s", path, v.Type(), name)
+		}
+		if !sf.IsExported() {
+			return reflect.Value{}, fmt.Errorf("field %s: %s is unexported", path, name)
+		}
+		v = v.FieldByIndex(sf.Index)
+	}
+	return v, nil
+}
+
+// convertNumber converts want to typ when both are numeric and the
+// conversion is lossless, and returns want unchanged otherwise
+func convertNumber(want any, typ reflect.Type) any {
+	wv := reflect.ValueOf(want)
+	if !wv.IsValid() || wv.Type() == typ || !isNumber(wv.Kind()) || !isNumber(typ.Kind()) {
+		return want
+	}
+
+	// a negative int would wrap around into an unsigned field and back
+	if wv.CanInt() && wv.Int() < 0 && typ.Kind() >= reflect.Uint {
+		return want
+	}
+	converted := wv.Convert(typ)
+	if !converted.Convert(wv.Type()).Equal(wv) {
+		return want
+	}
+	return converted.Interface()
+}
+
+// isNumber reports whether k is an integer or floating-point kind
+func isNumber(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Uint64) || k == reflect.Float32 || k == reflect.Float64
+}
+
+// AssertPanics is a helper to assert fn panics
+func AssertPanics(t *testing.T, fn func(), message string) {
+	t.Helper()
+	if panicked, _ := didPanic(fn); !panicked {
+		failf(t, "AssertPanics", nil, nil, "Expected panic but function did not panic: %s", message)
+	}
+}
+
+// AssertPanicsWithValue is a helper to assert fn panics with the expected value
+func AssertPanicsWithValue(t *testing.T, expected any, fn func()) {
+	t.Helper()
+	panicked, value := didPanic(fn)
+	if !panicked {
+		failf(t, "AssertPanicsWithValue", nil, expected, "Expected panic with %v but function did not panic", expected)
+	}
+	if !reflect.DeepEqual(value, expected) {
+		failf(t, "AssertPanicsWithValue", value, expected, "Got panic value %v (%T), want %v (%T)", value, value, expected, expected)
+	}
+}
+
+// AssertNotPanics is a helper to assert fn does not panic
+func AssertNotPanics(t *testing.T, fn func()) {
+	t.Helper()
+	if panicked, value := didPanic(fn); panicked {
+		failf(t, "AssertNotPanics", value, nil, "Unexpected panic: %v", value)
+	}
+}
+
+// didPanic runs fn and reports whether it panicked and the recovered value
+func didPanic(fn func()) (panicked bool, value any) {
+	defer func() {
+		if panicked {
+			value = recover()
+		}
+	}()
+
+	panicked = true
+	fn()
+	panicked = false
+	return
+}
+
+// AssertElementsMatch is a helper to assert two slices hold the same elements,
+// with the same multiplicity, in any order
+func AssertElementsMatch[T comparable](t *testing.T, got, want []T) {
+	t.Helper()
+
+	missing, extra := elementsDiff(got, want)
+	if len(missing) > 0 || len(extra) > 0 {
+		failf(t, "AssertElementsMatch", got, want, "Elements do not match: missing %v, extra %v\ngot:  %v\nwant: %v", missing, extra, got, want)
+	}
+}
+
+// AssertSubset is a helper to assert every element of subset appears in
+// superset, ignoring order, duplicates and extra elements
+func AssertSubset[T comparable](t *testing.T, subset, superset []T) {
+	t.Helper()
+
+	var missing []T
+	for _, v := range subset {
+		if !slices.Contains(superset, v) && !slices.Contains(missing, v) {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		failf(t, "AssertSubset", superset, subset, "Expected %v to contain all of %v, missing %v", superset, subset, missing)
+	}
+}
+
+// AssertSliceEqual is a helper to assert two slices are equal element by element
+func AssertSliceEqual[T comparable](t *testing.T, got, want []T) {
+	t.Helper()
+
+	var diffs []string
+	for i := 0; i < max(len(got), len(want)); i++ {
+		switch {
+		case i >= len(got):
+			diffs = append(diffs, fmt.Sprintf("[%d]: missing, want %v", i, want[i]))
+		case i >= len(want):
+			diffs = append(diffs, fmt.Sprintf("[%d]: got %v, want nothing", i, got[i]))
+		case got[i] != want[i]:
+			diffs = append(diffs, fmt.Sprintf("[%d]: got %v, want %v", i, got[i], want[i]))
+		}
+	}
+
+	if len(diffs) > 0 {
+		failf(t, "AssertSliceEqual", got, want, "Slices differ (got length %d, want %d):\n\t%s", len(got), len(want), strings.Join(diffs, "\n\t"))
+	}
+}
+
+// elementsDiff returns the elements of want missing from got and the
+// elements of got not in want, counting duplicates
+func elementsDiff[T comparable](got, want []T) (missing, extra []T) {
+	counts := make(map[T]int, len(want))
+	for _, v := range want {
+		counts[v]++
+	}
+	for _, v := range got {
+		if counts[v] > 0 {
+			counts[v]--
+		} else {
+			extra = append(extra, v)
+		}
+	}
+	for _, v := range want {
+		if counts[v] > 0 {
+			counts[v]--
+			missing = append(missing, v)
+		}
+	}
+	return missing, extra
+}
+
+// AssertSorted is a helper to assert a slice is ordered by less, i.e. no
+// element is less than the one before it. It reports the first pair out of
+// order.
+func AssertSorted[T any](t *testing.T, s []T, less func(a, b T) bool) {
+	t.Helper()
+
+	for i := 1; i < len(s); i++ {
+		if less(s[i], s[i-1]) {
+			failf(t, "AssertSorted", s, nil, "Slice is not sorted: [%d] %v should not come before [%d] %v", i-1, s[i-1], i, s[i])
+		}
+	}
+}
+
+// AssertSortedAsc is a helper to assert a slice is in ascending order,
+// allowing equal neighbours
+func AssertSortedAsc[T cmp.Ordered](t *testing.T, s []T) {
+	t.Helper()
+	AssertSorted(t, s, cmp.Less[T])
+}
+
+// AssertSortedDesc is a helper to assert a slice is in descending order,
+// allowing equal neighbours
+func AssertSortedDesc[T cmp.Ordered](t *testing.T, s []T) {
+	t.Helper()
+	AssertSorted(t, s, func(a, b T) bool { return cmp.Less(b, a) })
+}
+
+// AssertInDelta is a helper to assert two floats differ by at most delta
+func AssertInDelta(t *testing.T, got, want, delta float64) {
+	t.Helper()
+
+	diff := math.Abs(got - want)
+	if math.IsNaN(diff) || diff > delta {
+		failf(t, "AssertInDelta", got, want, "Got %v, want %v ± %v (difference %v)", got, want, delta, diff)
+	}
+}
+
+// AssertInEpsilon is a helper to assert two floats differ by at most epsilon
+// relative to want, e.g. 0.01 for 1%. want must be non-zero; use
+// AssertInDelta to compare against zero.
+func AssertInEpsilon(t *testing.T, got, want, epsilon float64) {
+	t.Helper()
+
+	if want == 0 {
+		t.Fatalf("Relative tolerance is undefined for want 0, use AssertInDelta")
+	}
+
+	relative := math.Abs(got-want) / math.Abs(want)
+	if math.IsNaN(relative) || relative > epsilon {
+		failf(t, "AssertInEpsilon", got, want, "Got %v, want %v within relative error %v (relative error %v)", got, want, epsilon, relative)
+	}
+}
+
+// AssertWithinDuration is a helper to assert two times are at most delta apart
+func AssertWithinDuration(t *testing.T, expected, actual time.Time, delta time.Duration) {
+	t.Helper()
+
+	diff := actual.Sub(expected)
+	if diff < -delta || diff > delta {
+		failf(t, "AssertWithinDuration", actual, expected, "Got %v, want %v ± %v (difference %v)", actual, expected, delta, diff)
+	}
+}
+
+// AssertCompletesWithin is a helper to assert fn returns within d, reporting
+// the elapsed time otherwise. fn runs in its own goroutine; if it hangs, that
+// goroutine cannot be stopped and keeps running after the test fails, so fn
+// should not touch state that later tests depend on. A panic in fn fails
+// the test instead of crashing the binary.
+func AssertCompletesWithin(t *testing.T, d time.Duration, fn func()) {
+	t.Helper()
+
+	done := make(chan any, 1)
+	start := time.Now()
+	go func() {
+		panicked := true
+		defer func() {
+			if panicked {
+				done <- recover()
+			}
+		}()
+		fn()
+		panicked = false
+		done <- nil
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		if r != nil {
+			failf(t, "AssertCompletesWithin", r, nil, "Function panicked after %s: %v", time.Since(start).Round(time.Millisecond), r)
+		}
+		if elapsed := time.Since(start); elapsed > d {
+			failf(t, "AssertCompletesWithin", elapsed, d, "Function took %s, want at most %s", elapsed.Round(time.Millisecond), d)
+		}
+	case <-timer.C:
+		failf(t, "AssertCompletesWithin", nil, d, "Function did not complete within %s", d)
+	}
+}
+
+// AssertReceive is a helper to assert a value arrives on ch within timeout,
+// and returns it. A closed channel fails the test, since it yields no value.
+func AssertReceive[T any](t *testing.T, ch <-chan T, timeout time.Duration) T {
+	t.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case v, ok := <-ch:
+		if !ok {
+			failf(t, "AssertReceive", "closed", nil, "Expected a value, but the channel was closed")
+		}
+		return v
+	case <-timer.C:
+		failf(t, "AssertReceive", nil, nil, "Expected a value within %s, received nothing", timeout)
+	}
+
+	var zero T
+	return zero
+}
+
+// AssertNoReceive is a helper to assert nothing arrives on ch for all of
+// timeout. A closed channel fails the test, since receiving from it never
+// blocks.
+func AssertNoReceive[T any](t *testing.T, ch <-chan T, timeout time.Duration) {
+	t.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case v, ok := <-ch:
+		if !ok {
+			failf(t, "AssertNoReceive", "closed", nil, "Expected no value within %s, but the channel was closed", timeout)
+		}
+		failf(t, "AssertNoReceive", v, nil, "Expected no value within %s, received %v", timeout, v)
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,133 @@
+package testing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const clickHouseImage = "clickhouse/clickhouse-server:24.8"
+
+// ClickHouseContainer wraps a ClickHouse test container. DB speaks the
+// native protocol through clickhouse-go's database/sql driver.
+type ClickHouseContainer struct {
+	Container testcontainers.Container
+	DB        *sql.DB
+	DSN       string
+}
+
+// SetupClickHouse creates a ClickHouse test container (user/password: test,
+// database: testdb)
+func SetupClickHouse(t *testing.T) *ClickHouseContainer {
+	t.Helper()
+
+	return SetupClickHouseCtx(context.Background(), t)
+}
+
+// SetupClickHouseCtx is SetupClickHouse with a caller-supplied context
+// bounding container startup and the initial connection
+func SetupClickHouseCtx(ctx context.Context, t *testing.T) *ClickHouseContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        clickHouseImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"9000/tcp", "8123/tcp"},
+		Env: map[string]string{
+			"CLICKHOUSE_USER":     "test",
+			"CLICKHOUSE_PASSWORD": "test",
+			"CLICKHOUSE_DB":       "testdb",
+		},
+		WaitingFor: wait.ForHTTP("/ping").
+			WithPort("8123/tcp").
+			WithStartupTimeout(60 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start ClickHouse container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "9000")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	dsn := fmt.Sprintf("clickhouse://test:test@%s:%s/testdb", host, port.Port())
+	db, err := sql.Open("clickhouse", dsn)
+	if err != nil {
+		t.Fatalf("Failed to connect to ClickHouse: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	// /ping answers before the entrypoint has created the user and database
+	err = waitForHealthy(ctx, container, connectTimeout, func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	})
+	if err != nil {
+		t.Fatalf("Failed to ping ClickHouse: %v", err)
+	}
+
+	return &ClickHouseContainer{
+		Container: container,
+		DB:        db,
+		DSN:       dsn,
+	}
+}
+
+// TruncateClickHouseTables truncates the given tables, or every table in the
+// current database when none are given. ClickHouse truncates one table per
+// statement and has no RESTART IDENTITY or CASCADE; views are skipped.
+func TruncateClickHouseTables(t *testing.T, db *sql.DB, tables ...string) {
+	t.Helper()
+
+	if len(tables) == 0 {
+		rows, err := db.Query("SELECT name FROM system.tables " +
+			"WHERE database = currentDatabase() AND engine NOT IN ('View', 'LiveView', 'Dictionary')")
+		if err != nil {
+			t.Fatalf("Failed to list ClickHouse tables: %v", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				t.Fatalf("Failed to list ClickHouse tables: %v", err)
+			}
+			tables = append(tables, name)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatalf("Failed to list ClickHouse tables: %v", err)
+		}
+	}
+
+	for _, table := range tables {
+		quoted := "`" + strings.ReplaceAll(table, "`", "\\`") + "`"
+		if _, err := db.Exec("TRUNCATE TABLE " + quoted); err != nil {
+			t.Fatalf("Failed to truncate table %s: %v", table, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package testing
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Clock abstracts time.Now so code under test can be given a controllable
+// clock. Production code takes a Clock (defaulting to RealClock) instead of
+// calling time.Now directly.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is a Clock backed by time.Now
+type RealClock struct{}
+
+// Now returns the current time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FrozenClock is a Clock that only moves when told to. It is safe for
+// concurrent use.
+type FrozenClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFrozenClock returns a FrozenClock stopped at the given time
+func NewFrozenClock(at time.Time) *FrozenClock {
+	return &FrozenClock{now: at}
+}
+
+// Now returns the clock's current time
+func (c *FrozenClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d
+func (c *FrozenClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to the given time
+func (c *FrozenClock) Set(at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = at
+}
+
+// SetupFrozenClock returns a FrozenClock stopped at the given time, or at a
+// fixed reference time (2024-01-01 00:00:00 UTC) if at is zero
+func SetupFrozenClock(t *testing.T, at time.Time) *FrozenClock {
+	t.Helper()
+
+	if at.IsZero() {
+		at = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return NewFrozenClock(at)
+}
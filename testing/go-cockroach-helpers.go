@@ -0,0 +1,102 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const cockroachImage = "cockroachdb/cockroach:latest"
+
+// CockroachContainer wraps a single-node CockroachDB test container
+type CockroachContainer struct {
+	Container testcontainers.Container
+	DB        *gorm.DB
+	DSN       string
+}
+
+// SetupCockroach creates a single-node, insecure CockroachDB test container.
+// CockroachDB speaks the Postgres wire protocol, so DB uses the gorm postgres
+// driver.
+func SetupCockroach(t *testing.T) *CockroachContainer {
+	t.Helper()
+
+	return SetupCockroachCtx(context.Background(), t)
+}
+
+// SetupCockroachCtx is SetupCockroach with a caller-supplied context
+// bounding container startup and the initial connection
+func SetupCockroachCtx(ctx context.Context, t *testing.T) *CockroachContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        cockroachImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"26257/tcp", "8080/tcp"},
+		Cmd:          []string{"start-single-node", "--insecure"},
+		// The node logs nothing stable on readiness; the HTTP health
+		// endpoint reports ready once it accepts SQL connections
+		WaitingFor: wait.ForHTTP("/health?ready=1").
+			WithPort("8080/tcp").
+			WithStartupTimeout(90 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start CockroachDB container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "26257")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%s user=root dbname=defaultdb sslmode=disable",
+		host, port.Port())
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get database handle: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	err = waitForHealthy(ctx, container, connectTimeout, func(ctx context.Context) error {
+		return sqlDB.PingContext(ctx)
+	})
+	if err != nil {
+		t.Fatalf("Failed to ping database: %v", err)
+	}
+
+	return &CockroachContainer{
+		Container: container,
+		DB:        db,
+		DSN:       dsn,
+	}
+}
@@ -0,0 +1,182 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/moby/moby/client"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	// labelTestName and labelTestPackage are set on every container started
+	// by this package so orphans can be traced back to their test
+	labelTestName    = "test.name"
+	labelTestPackage = "test.package"
+
+	// containerNamePrefixEnv, when set, gives containers readable names of
+	// the form <prefix>-<test name>-<random>
+	containerNamePrefixEnv = "TEST_CONTAINER_NAME_PREFIX"
+
+	// sharedTestName labels containers that outlive a single test
+	sharedTestName = "shared"
+)
+
+// invalidNameChars matches characters Docker rejects in container names
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// testPackage returns the import path of the package under test, read from
+// the test binary's build info ("<import path>.test")
+var testPackage = sync.OnceValue(func() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Path != "" {
+		return strings.TrimSuffix(info.Path, ".test")
+	}
+	return strings.TrimSuffix(filepath.Base(os.Args[0]), ".test")
+})
+
+// containerLabels returns the labels identifying a container started for
+// testName
+func containerLabels(testName string) map[string]string {
+	return map[string]string{
+		labelTestName:    testName,
+		labelTestPackage: testPackage(),
+	}
+}
+
+// containerName returns a readable container name for testName, or "" to
+// let Docker pick one when TEST_CONTAINER_NAME_PREFIX is unset
+func containerName(testName string) string {
+	prefix := os.Getenv(containerNamePrefixEnv)
+	if prefix == "" {
+		return ""
+	}
+	name := invalidNameChars.ReplaceAllString(testName, "_")
+	return fmt.Sprintf("%s-%s-%s", prefix, name, randomSuffix())
+}
+
+// CleanupOrphans force-removes containers labelled with the current test
+// package, such as those left running when a previous run crashed. Call it
+// from TestMain before m.Run. Containers of the same package started by a
+// concurrent run on the same Docker host are removed too.
+//
+// Orphans of any package can be removed from a shell with:
+//
+//	docker rm -f $(docker ps -aq --filter label=test.package)
+func CleanupOrphans() error {
+	ctx := context.Background()
+
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to create Docker client: %w", err)
+	}
+	defer cli.Close()
+
+	list, err := cli.ContainerList(ctx, client.ContainerListOptions{
+		All:     true,
+		Filters: make(client.Filters).Add("label", labelTestPackage+"="+testPackage()),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to list containers: %w", err)
+	}
+
+	for _, c := range list.Items {
+		if _, err := cli.ContainerRemove(ctx, c.ID, client.ContainerRemoveOptions{Force: true, RemoveVolumes: true}); err != nil {
+			return fmt.Errorf("Failed to remove container %s: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+// dumpLogsOnFailure registers a cleanup that writes the container's logs to
+// the test output if the test failed. Cleanups run in reverse order, so call
+// it after registering the cleanup that terminates the container.
+func dumpLogsOnFailure(t *testing.T, container testcontainers.Container) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+
+		logs, err := container.Logs(context.Background())
+		if err != nil {
+			t.Logf("Failed to read container logs: %v", err)
+			return
+		}
+		defer logs.Close()
+
+		data, err := io.ReadAll(logs)
+		if err != nil {
+			t.Logf("Failed to read container logs: %v", err)
+			return
+		}
+		t.Logf("Container %s logs:\n%s", container.GetContainerID(), data)
+	})
+}
+
+// DisableReaper turns off Ryuk, the reaper container testcontainers starts
+// to remove containers left behind by a killed test process, for CI hosts
+// that forbid it. Containers are then only removed by t.Cleanup and
+// TerminateSharedContainers, so a process killed mid-run (timeout, SIGKILL)
+// leaks them.
+//
+// testcontainers reads its configuration once, so call it from TestMain
+// before any container starts. Setting TESTCONTAINERS_RYUK_DISABLED=true in
+// the CI environment has the same effect without code changes.
+func DisableReaper() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+}
+
+// defaultImages are the images started by this package's Setup* functions
+// when no image option is given
+var defaultImages = []string{
+	defaultPostgresImage,
+	defaultRedisImage,
+	defaultRedisClusterImage,
+	mysqlImage,
+	mongoImage,
+	kafkaImage,
+	rabbitMQImage,
+	localStackImage,
+	minioImage,
+	elasticsearchImage,
+	natsImage,
+	cockroachImage,
+	mailpitImage,
+	clickHouseImage,
+	dynamoDBImage,
+	jaegerImage,
+	vaultImage,
+}
+
+// Prepull pulls images up front, so the first test using each one only waits
+// for the container to boot instead of the download. With no arguments it
+// pulls every default image of this package; pass only the images a suite
+// uses to avoid downloading the rest. Call it from TestMain before m.Run,
+// with a context bounding the total pull time.
+func Prepull(ctx context.Context, images ...string) error {
+	if len(images) == 0 {
+		images = defaultImages
+	}
+
+	provider, err := testcontainers.NewDockerProvider()
+	if err != nil {
+		return fmt.Errorf("Failed to create Docker provider: %w", err)
+	}
+	defer provider.Close()
+
+	for _, image := range images {
+		if err := provider.PullImage(ctx, image); err != nil {
+			return fmt.Errorf("Failed to pull image %s: %w", image, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package testing
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AssertRowCount is a helper to assert a table has the expected number of
+// rows
+func AssertRowCount(t *testing.T, db *gorm.DB, table string, want int64) {
+	t.Helper()
+
+	var got int64
+	if err := db.Table(table).Count(&got).Error; err != nil {
+		t.Fatalf("Failed to count rows in %s: %v", table, err)
+	}
+	if got != want {
+		failf(t, "AssertRowCount", got, want, "Got %d rows in %s, want %d", got, table, want)
+	}
+}
+
+// AssertRowExists is a helper to assert a row matching conds exists. model
+// is a pointer to a model; conds are gorm inline conditions such as
+// "email = ?", "alice@example.com". The found row is loaded into model.
+func AssertRowExists(t *testing.T, db *gorm.DB, model interface{}, conds ...interface{}) {
+	t.Helper()
+
+	err := db.First(model, conds...).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		failf(t, "AssertRowExists", nil, conds, "Expected a %T row matching %v, found none", model, conds)
+	}
+	if err != nil {
+		t.Fatalf("Failed to query %T: %v", model, err)
+	}
+}
+
+// AssertSoftDeleted is a helper to assert the row with primary key id is
+// hidden from normal queries but still present with Unscoped, i.e. its
+// DeletedAt is set. model is a pointer to the model type, e.g. &User{}.
+func AssertSoftDeleted(t *testing.T, db *gorm.DB, model interface{}, id interface{}) {
+	t.Helper()
+
+	visible := findByID(t, db, model, id)
+	present := findByID(t, db.Unscoped(), model, id)
+	switch {
+	case visible:
+		failf(t, "AssertSoftDeleted", "not deleted", "soft-deleted", "Expected %T %v to be soft-deleted, but it is still visible to normal queries", model, id)
+	case !present:
+		failf(t, "AssertSoftDeleted", "hard-deleted", "soft-deleted", "Expected %T %v to be soft-deleted, but it was hard-deleted", model, id)
+	}
+}
+
+// AssertHardDeleted is a helper to assert the row with primary key id is gone
+// even with Unscoped. model is a pointer to the model type, e.g. &User{}.
+func AssertHardDeleted(t *testing.T, db *gorm.DB, model interface{}, id interface{}) {
+	t.Helper()
+
+	visible := findByID(t, db, model, id)
+	present := findByID(t, db.Unscoped(), model, id)
+	switch {
+	case visible:
+		failf(t, "AssertHardDeleted", "not deleted", "hard-deleted", "Expected %T %v to be hard-deleted, but it was not deleted", model, id)
+	case present:
+		failf(t, "AssertHardDeleted", "soft-deleted", "hard-deleted", "Expected %T %v to be hard-deleted, but it was only soft-deleted", model, id)
+	}
+}
+
+// PostgreSQL SQLSTATE codes checked by the constraint assertions
+const (
+	sqlStateUniqueViolation     = "23505"
+	sqlStateForeignKeyViolation = "23503"
+)
+
+// sqlStateError is implemented by pgx's *pgconn.PgError and lib/pq's *pq.Error
+type sqlStateError interface {
+	error
+	SQLState() string
+}
+
+// AssertUniqueViolation is a helper to assert err is a unique constraint
+// violation (SQLSTATE 23505) anywhere in its chain, or gorm.ErrDuplicatedKey
+// when the DB translates errors
+func AssertUniqueViolation(t *testing.T, err error) {
+	t.Helper()
+
+	assertConstraintViolation(t, "AssertUniqueViolation", err, "unique", sqlStateUniqueViolation, gorm.ErrDuplicatedKey)
+}
+
+// AssertForeignKeyViolation is a helper to assert err is a foreign key
+// violation (SQLSTATE 23503) anywhere in its chain, or
+// gorm.ErrForeignKeyViolated when the DB translates errors
+func AssertForeignKeyViolation(t *testing.T, err error) {
+	t.Helper()
+
+	assertConstraintViolation(t, "AssertForeignKeyViolation", err, "foreign key", sqlStateForeignKeyViolation, gorm.ErrForeignKeyViolated)
+}
+
+// assertConstraintViolation fails unless err carries the given SQLSTATE or
+// wraps translated
+func assertConstraintViolation(t *testing.T, assertion string, err error, kind, code string, translated error) {
+	t.Helper()
+
+	if errors.Is(err, translated) {
+		return
+	}
+
+	var stateErr sqlStateError
+	switch {
+	case err == nil:
+		failf(t, assertion, nil, code, "Expected a %s violation (%s), got nil", kind, code)
+	case !errors.As(err, &stateErr):
+		failf(t, assertion, err, code, "Expected a %s violation (%s), got a non-database error, chain:%s", kind, code, errorChain(err))
+	case stateErr.SQLState() != code:
+		failf(t, assertion, stateErr.SQLState(), code, "Expected a %s violation (%s), got SQLSTATE %s: %v", kind, code, stateErr.SQLState(), err)
+	}
+}
+
+// findByID reports whether a row of model's type with primary key id exists.
+// It loads into a fresh value so the caller's model is left untouched.
+func findByID(t *testing.T, db *gorm.DB, model interface{}, id interface{}) bool {
+	t.Helper()
+
+	// db.First(dest, id) would inline a string id as raw SQL, so match the
+	// primary key column explicitly
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		t.Fatalf("Failed to parse model %T: %v", model, err)
+	}
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		t.Fatalf("Model %T has no primary key", model)
+	}
+	pk := stmt.Schema.PrioritizedPrimaryField.DBName
+
+	dest := reflect.New(reflect.TypeOf(model).Elem()).Interface()
+	err := db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: pk}, Value: id}).First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false
+	}
+	if err != nil {
+		t.Fatalf("Failed to query %T %v: %v", model, id, err)
+	}
+	return true
+}
+
+// QueryRow runs a query returning a single column and row, such as
+// "SELECT status FROM orders WHERE id = ?", and scans the value into T
+func QueryRow[T any](t *testing.T, db *gorm.DB, query string, args ...any) T {
+	t.Helper()
+
+	var value T
+	err := db.Raw(query, args...).Row().Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Query returned no rows: %s", query)
+	}
+	if err != nil {
+		t.Fatalf("Failed to query %s: %v", query, err)
+	}
+	return value
+}
+
+// QueryRows runs a query returning a single column and scans every row
+// into a slice of T
+func QueryRows[T any](t *testing.T, db *gorm.DB, query string, args ...any) []T {
+	t.Helper()
+
+	rows, err := db.Raw(query, args...).Rows()
+	if err != nil {
+		t.Fatalf("Failed to query %s: %v", query, err)
+	}
+	defer rows.Close()
+
+	var values []T
+	for rows.Next() {
+		var value T
+		if err := rows.Scan(&value); err != nil {
+			t.Fatalf("Failed to scan row %d of %s: %v", len(values), query, err)
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Failed to read rows of %s: %v", query, err)
+	}
+	return values
+}
+
+// SnapshotTables dumps the contents of each table, ordered by its first
+// column, for comparison with AssertTablesUnchanged. Byte values are stored
+// as strings so they diff readably.
+func SnapshotTables(t *testing.T, db *gorm.DB, tables ...string) map[string][]map[string]any {
+	t.Helper()
+
+	snapshot := make(map[string][]map[string]any, len(tables))
+	for _, table := range tables {
+		var rows []map[string]any
+		if err := db.Table(table).Order("1").Find(&rows).Error; err != nil {
+			t.Fatalf("Failed to snapshot table %s: %v", table, err)
+		}
+		for _, row := range rows {
+			for column, value := range row {
+				if b, ok := value.([]byte); ok {
+					row[column] = string(b)
+				}
+			}
+		}
+		snapshot[table] = rows
+	}
+	return snapshot
+}
+
+// AssertTablesUnchanged is a helper to assert two SnapshotTables results hold
+// the same rows. Rows are matched by their id column when the table has one,
+// otherwise by position, and every added, removed or changed row is reported.
+func AssertTablesUnchanged(t *testing.T, before, after map[string][]map[string]any) {
+	t.Helper()
+
+	tables := slices.Sorted(maps.Keys(before))
+	for table := range after {
+		if _, ok := before[table]; !ok {
+			tables = append(tables, table)
+		}
+	}
+	slices.Sort(tables)
+
+	var diffs []string
+	for _, table := range tables {
+		diffs = append(diffs, diffTableRows(table, before[table], after[table])...)
+	}
+	if len(diffs) > 0 {
+		failf(t, "AssertTablesUnchanged", nil, nil, "Tables changed:\n\t%s", strings.Join(diffs, "\n\t"))
+	}
+}
+
+// diffTableRows describes the differences between two snapshots of a table
+func diffTableRows(table string, before, after []map[string]any) []string {
+	beforeRows, order := keyRows(before)
+	afterRows, afterOrder := keyRows(after)
+	for _, key := range afterOrder {
+		if _, ok := beforeRows[key]; !ok {
+			order = append(order, key)
+		}
+	}
+
+	var diffs []string
+	for _, key := range order {
+		old, hadOld := beforeRows[key]
+		cur, hasCur := afterRows[key]
+		switch {
+		case !hasCur:
+			diffs = append(diffs, fmt.Sprintf("%s %s: removed %v", table, key, old))
+		case !hadOld:
+			diffs = append(diffs, fmt.Sprintf("%s %s: added %v", table, key, cur))
+		default:
+			columns := slices.Sorted(maps.Keys(old))
+			for column := range cur {
+				if _, ok := old[column]; !ok {
+					columns = append(columns, column)
+				}
+			}
+			for _, column := range columns {
+				if !reflect.DeepEqual(old[column], cur[column]) {
+					diffs = append(diffs, fmt.Sprintf("%s %s: %s changed from %v to %v", table, key, column, old[column], cur[column]))
+				}
+			}
+		}
+	}
+	return diffs
+}
+
+// keyRows indexes rows by "id=<value>" when they have an id column, or by
+// "row <index>" otherwise, returning the keys in their original order
+func keyRows(rows []map[string]any) (map[string]map[string]any, []string) {
+	keyed := make(map[string]map[string]any, len(rows))
+	order := make([]string, 0, len(rows))
+	for i, row := range rows {
+		key := fmt.Sprintf("row %d", i)
+		if id, ok := row["id"]; ok {
+			key = fmt.Sprintf("id=%v", id)
+		}
+		keyed[key] = row
+		order = append(order, key)
+	}
+	return keyed, order
+}
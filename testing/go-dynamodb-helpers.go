@@ -0,0 +1,130 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const dynamoDBImage = "amazon/dynamodb-local:2.5.2"
+
+// tableActiveTimeout bounds how long CreateTable waits for a table to become
+// ACTIVE; dynamodb-local usually reports it immediately
+const tableActiveTimeout = 30 * time.Second
+
+// DynamoDBContainer wraps a dynamodb-local test container. Client is
+// configured for Endpoint with dummy credentials.
+type DynamoDBContainer struct {
+	Container testcontainers.Container
+	Client    *dynamodb.Client
+	Endpoint  string
+}
+
+// SetupDynamoDB creates an in-memory dynamodb-local test container
+func SetupDynamoDB(t *testing.T) *DynamoDBContainer {
+	t.Helper()
+
+	return SetupDynamoDBCtx(context.Background(), t)
+}
+
+// SetupDynamoDBCtx is SetupDynamoDB with a caller-supplied context bounding
+// container startup
+func SetupDynamoDBCtx(ctx context.Context, t *testing.T) *DynamoDBContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        dynamoDBImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"8000/tcp"},
+		// -sharedDb ignores the region and access key, so every client
+		// configuration sees the same tables
+		Cmd: []string{"-jar", "DynamoDBLocal.jar", "-inMemory", "-sharedDb"},
+		WaitingFor: wait.ForListeningPort("8000/tcp").
+			WithStartupTimeout(60 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start DynamoDB container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "8000")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("http://%s:%s", host, port.Port())
+	cfg, err := localAWSConfig(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("Failed to load AWS config: %v", err)
+	}
+
+	return &DynamoDBContainer{
+		Container: container,
+		Client:    dynamodb.NewFromConfig(cfg),
+		Endpoint:  endpoint,
+	}
+}
+
+// CreateTable creates a DynamoDB table and waits until it is ACTIVE
+func CreateTable(t *testing.T, client *dynamodb.Client, input *dynamodb.CreateTableInput) {
+	t.Helper()
+
+	ctx := context.Background()
+	name := aws.ToString(input.TableName)
+
+	if _, err := client.CreateTable(ctx, input); err != nil {
+		t.Fatalf("Failed to create DynamoDB table %s: %v", name, err)
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(client)
+	err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: input.TableName}, tableActiveTimeout)
+	if err != nil {
+		t.Fatalf("Failed to wait for DynamoDB table %s: %v", name, err)
+	}
+}
+
+// DeleteAllTables deletes every table, for a clean slate between tests
+// sharing one container
+func DeleteAllTables(t *testing.T, client *dynamodb.Client) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	// list everything first, as deleting mid-pagination shifts the pages
+	var names []string
+	paginator := dynamodb.NewListTablesPaginator(client, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			t.Fatalf("Failed to list DynamoDB tables: %v", err)
+		}
+		names = append(names, page.TableNames...)
+	}
+
+	for _, name := range names {
+		_, err := client.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: aws.String(name)})
+		if err != nil {
+			t.Fatalf("Failed to delete DynamoDB table %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const elasticsearchImage = "docker.elastic.co/elasticsearch/elasticsearch:8.15.3"
+
+// ElasticsearchContainer wraps an Elasticsearch test container
+type ElasticsearchContainer struct {
+	Container testcontainers.Container
+	Client    *elasticsearch.Client
+	URL       string
+}
+
+// SetupElasticsearch creates a single-node Elasticsearch test container with
+// security disabled
+func SetupElasticsearch(t *testing.T) *ElasticsearchContainer {
+	t.Helper()
+
+	return SetupElasticsearchCtx(context.Background(), t)
+}
+
+// SetupElasticsearchCtx is SetupElasticsearch with a caller-supplied context
+// bounding container startup
+func SetupElasticsearchCtx(ctx context.Context, t *testing.T) *ElasticsearchContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        elasticsearchImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"9200/tcp"},
+		Env: map[string]string{
+			"discovery.type":         "single-node",
+			"xpack.security.enabled": "false",
+			"ES_JAVA_OPTS":           "-Xms512m -Xmx512m",
+		},
+		WaitingFor: wait.ForHTTP("/_cluster/health?wait_for_status=yellow&timeout=60s").
+			WithPort("9200/tcp").
+			WithStartupTimeout(120 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Elasticsearch container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "9200")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	url := fmt.Sprintf("http://%s:%s", host, port.Port())
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{url},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Elasticsearch client: %v", err)
+	}
+
+	return &ElasticsearchContainer{
+		Container: container,
+		Client:    client,
+		URL:       url,
+	}
+}
+
+// CreateIndex creates an index with the given JSON mapping (may be empty)
+func CreateIndex(t *testing.T, client *elasticsearch.Client, name, mapping string) {
+	t.Helper()
+
+	opts := []func(*esapi.IndicesCreateRequest){}
+	if mapping != "" {
+		opts = append(opts, client.Indices.Create.WithBody(strings.NewReader(mapping)))
+	}
+
+	res, err := client.Indices.Create(name, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create index %s: %v", name, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		t.Fatalf("Failed to create index %s: %s", name, res.String())
+	}
+}
+
+// RefreshIndex makes recently indexed documents visible to search. Elasticsearch
+// is near-real-time, so call it between indexing and asserting on search results.
+func RefreshIndex(t *testing.T, client *elasticsearch.Client, name string) {
+	t.Helper()
+
+	res, err := client.Indices.Refresh(client.Indices.Refresh.WithIndex(name))
+	if err != nil {
+		t.Fatalf("Failed to refresh index %s: %v", name, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		t.Fatalf("Failed to refresh index %s: %s", name, res.String())
+	}
+}
@@ -0,0 +1,75 @@
+package testing
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// SetEnv sets an environment variable until the test ends, then restores its
+// previous value or unsets it if it was not set. It is t.Setenv, so it panics
+// in parallel tests, whose environment is shared by the whole process.
+func SetEnv(t *testing.T, key, value string) {
+	t.Helper()
+
+	t.Setenv(key, value)
+}
+
+// runWithEnvMarker is set by RunWithEnv for the rest of the test, so that
+// t.Setenv rejects parallel use the same way it does for SetEnv
+const runWithEnvMarker = "TEST_RUN_WITH_ENV"
+
+// RunWithEnv sets the given environment variables, runs fn and restores them
+// as soon as fn returns, including when it fails the test or panics. Like
+// SetEnv it fails in parallel tests.
+func RunWithEnv(t *testing.T, vars map[string]string, fn func()) {
+	t.Helper()
+
+	if err := checkSerialTest(t); err != nil {
+		t.Fatalf("RunWithEnv cannot be used in parallel tests: %v", err)
+	}
+
+	var restores []func()
+	defer func() {
+		for i := len(restores) - 1; i >= 0; i-- {
+			restores[i]()
+		}
+	}()
+	for key, value := range vars {
+		restores = append(restores, setEnv(t, key, value))
+	}
+
+	fn()
+}
+
+// checkSerialTest returns an error if the test or an ancestor called
+// t.Parallel, and otherwise makes a later t.Parallel call panic
+func checkSerialTest(t *testing.T) (err error) {
+	t.Helper()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	t.Setenv(runWithEnvMarker, "1")
+	return nil
+}
+
+// setEnv sets key to value and returns a function restoring its previous state
+func setEnv(t *testing.T, key, value string) func() {
+	t.Helper()
+
+	previous, existed := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatalf("Failed to set environment variable %s: %v", key, err)
+	}
+
+	return func() {
+		if existed {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
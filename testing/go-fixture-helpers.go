@@ -0,0 +1,69 @@
+package testing
+
+import (
+	"os"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// LoadFixtures inserts each record with db.Create in argument order, so
+// parents listed before children satisfy foreign keys. Records may be
+// pointers to models or slices of models.
+func LoadFixtures(t *testing.T, db *gorm.DB, records ...interface{}) {
+	t.Helper()
+
+	for i, record := range records {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to load fixture %d (%T): %v", i, record, err)
+		}
+	}
+}
+
+// LoadFixturesFromYAML inserts the rows of a YAML file keyed by table name.
+// Tables are loaded in file order, rows in list order:
+//
+//	users:
+//	  - id: 1
+//	    email: alice@example.com
+//	orders:
+//	  - user_id: 1
+//	    total: 100
+func LoadFixturesFromYAML(t *testing.T, db *gorm.DB, path string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read fixture file %s: %v", path, err)
+	}
+
+	// Decode into a node rather than a map to keep the table order
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to parse fixture file %s: %v", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		t.Fatalf("Fixture file %s must map table names to lists of rows", path)
+	}
+
+	for i := 0; i < len(root.Content); i += 2 {
+		table := root.Content[i].Value
+
+		var rows []map[string]interface{}
+		if err := root.Content[i+1].Decode(&rows); err != nil {
+			t.Fatalf("Failed to decode rows of table %s in %s: %v", table, path, err)
+		}
+
+		for j, row := range rows {
+			if err := db.Table(table).Create(row).Error; err != nil {
+				t.Fatalf("Failed to insert row %d of table %s from %s: %v", j, table, path, err)
+			}
+		}
+	}
+}
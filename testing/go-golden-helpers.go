@@ -0,0 +1,54 @@
+package testing
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the actual output")
+
+// AssertGolden compares actual against the contents of the golden file. When
+// the tests run with -update, the golden file is rewritten with actual instead.
+func AssertGolden(t *testing.T, golden string, actual []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+			t.Fatalf("Failed to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(golden, actual, 0o644); err != nil {
+			t.Fatalf("Failed to update golden file %s: %v", golden, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read golden file %s (run with -update to create it): %v", golden, err)
+	}
+
+	if !bytes.Equal(want, actual) {
+		failf(t, "AssertGolden", nil, nil, "Output does not match golden file %s:\n%s",
+			golden, unifiedDiff(golden, "actual", string(want), string(actual)))
+	}
+}
+
+// unifiedDiff renders a unified diff between two texts
+func unifiedDiff(fromName, toName, from, to string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+	if err != nil {
+		return err.Error()
+	}
+	return diff
+}
@@ -0,0 +1,110 @@
+package testing
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// goroutineLeakGrace is how long AssertNoGoroutineLeak waits for goroutines
+// that are already shutting down to exit
+const goroutineLeakGrace = 2 * time.Second
+
+// defaultGoroutineLeakIgnores are stack substrings of goroutines that outlive
+// a test by design: other tests and testcontainers' Docker client and reaper
+var defaultGoroutineLeakIgnores = []string{
+	"testing.tRunner",
+	"testing.(*T).Run",
+	"github.com/testcontainers/testcontainers-go",
+	"github.com/docker/docker",
+	"os/signal.signal_recv",
+	"runtime.ensureSigM",
+}
+
+// IgnoreHTTPKeepAlive matches the goroutines of idle keep-alive HTTP
+// connections. They are reported by default, since an unclosed response body
+// or client is a common leak; pass it to AssertNoGoroutineLeak when a shared
+// transport, such as the Docker client of a test starting containers, is
+// expected to keep connections open.
+const IgnoreHTTPKeepAlive = "net/http.(*persistConn)"
+
+// AssertNoGoroutineLeak records the running goroutines and, when the test
+// finishes, fails if goroutines started since then are still running,
+// printing their stacks. Call it first so its check runs after every other
+// cleanup, including container and connection teardown. Stacks containing
+// any of ignore are skipped in addition to the defaults.
+func AssertNoGoroutineLeak(t *testing.T, ignore ...string) {
+	t.Helper()
+
+	before := make(map[string]bool)
+	for _, g := range goroutineStacks() {
+		before[g.id] = true
+	}
+	ignores := append(slices.Clone(defaultGoroutineLeakIgnores), ignore...)
+
+	t.Cleanup(func() {
+		var leaked []goroutineStack
+		_, _, ok := poll(goroutineLeakGrace, WaitOptions{}, func() bool {
+			leaked = leaked[:0]
+			for _, g := range goroutineStacks() {
+				if before[g.id] || g.matchesAny(ignores) {
+					continue
+				}
+				leaked = append(leaked, g)
+			}
+			return len(leaked) == 0
+		})
+		if ok {
+			return
+		}
+
+		stacks := make([]string, len(leaked))
+		for i, g := range leaked {
+			stacks[i] = g.stack
+		}
+		errorf(t, "AssertNoGoroutineLeak", len(leaked), 0, "Found %d leaked goroutines:\n\n%s", len(leaked), strings.Join(stacks, "\n\n"))
+	})
+}
+
+// goroutineStack is one goroutine from a runtime.Stack dump
+type goroutineStack struct {
+	id    string
+	stack string
+}
+
+func (g goroutineStack) matchesAny(substrs []string) bool {
+	for _, s := range substrs {
+		if strings.Contains(g.stack, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// goroutineStacks returns every goroutine except the calling one
+func goroutineStacks() []goroutineStack {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	// The dump is "goroutine <id> [<state>]:\n<frames>" blocks separated by
+	// blank lines, starting with the calling goroutine
+	blocks := strings.Split(string(buf), "\n\n")
+	var stacks []goroutineStack
+	for _, block := range blocks[1:] {
+		fields := strings.Fields(block)
+		if len(fields) < 2 || fields[0] != "goroutine" {
+			continue
+		}
+		stacks = append(stacks, goroutineStack{id: fields[1], stack: block})
+	}
+	return stacks
+}
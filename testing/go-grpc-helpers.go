@@ -0,0 +1,122 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufconnSize = 1024 * 1024
+
+// SetupGRPCServer starts an in-process gRPC server on an in-memory bufconn
+// listener and returns a client connection to it. register is called with the
+// server before it starts serving. The server and connection are closed when
+// the test ends.
+func SetupGRPCServer(t *testing.T, register func(*grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(bufconnSize)
+	server := grpc.NewServer()
+	register(server)
+
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		server.Stop()
+		t.Fatalf("Failed to dial gRPC server: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return conn
+}
+
+// grpcCallTimeout bounds each call the gRPC assertions make
+const grpcCallTimeout = 5 * time.Second
+
+// AssertGRPCServing is a helper to assert the standard health service
+// reports SERVING for service, or for the server as a whole when service is
+// empty. Register it with health.NewServer() in SetupGRPCServer.
+func AssertGRPCServing(t *testing.T, conn *grpc.ClientConn, service string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), grpcCallTimeout)
+	defer cancel()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Failed to check health of %q: %v", service, err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		failf(t, "AssertGRPCServing", resp.GetStatus(), healthpb.HealthCheckResponse_SERVING,
+			"Service %q is %s, want SERVING", service, resp.GetStatus())
+	}
+}
+
+// AssertGRPCServiceRegistered is a helper to assert the server exposes a
+// service, by its full name such as "users.v1.UserService", through server
+// reflection. Register reflection with reflection.Register in
+// SetupGRPCServer.
+func AssertGRPCServiceRegistered(t *testing.T, conn *grpc.ClientConn, fullName string) {
+	t.Helper()
+
+	services, err := listGRPCServices(conn)
+	if err != nil {
+		t.Fatalf("Failed to list gRPC services: %v", err)
+	}
+	if !slices.Contains(services, fullName) {
+		failf(t, "AssertGRPCServiceRegistered", services, fullName,
+			"Expected service %s to be registered, got %v", fullName, services)
+	}
+}
+
+// listGRPCServices asks the reflection service for every registered service
+func listGRPCServices(conn *grpc.ClientConn) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), grpcCallTimeout)
+	defer cancel()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, fmt.Errorf("reflection error %d: %s", errResp.GetErrorCode(), errResp.GetErrorMessage())
+	}
+
+	var services []string
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		services = append(services, svc.GetName())
+	}
+	return services, nil
+}
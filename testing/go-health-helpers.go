@@ -0,0 +1,54 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// WaitForHealthy runs probe against a started container every 500ms until it
+// returns nil, for images whose readiness the built-in wait strategies can't
+// express, e.g. a query or HTTP call against a custom entrypoint. It fails
+// the test with the last probe error after timeout, or right away if the
+// container exits. The Setup* functions use the same loop for their connect
+// step.
+func WaitForHealthy(t *testing.T, container testcontainers.Container, probe func(ctx context.Context) error, timeout time.Duration) {
+	t.Helper()
+
+	if err := waitForHealthy(context.Background(), container, timeout, probe); err != nil {
+		t.Fatalf("Container did not become healthy: %v", err)
+	}
+}
+
+// waitForHealthy calls probe until it succeeds, timeout passes, ctx is done
+// or the container stops running. probe receives a context that ends with
+// the wait, so a hung attempt can't outlast it.
+func waitForHealthy(ctx context.Context, container testcontainers.Container, timeout time.Duration, probe func(ctx context.Context) error) error {
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for attempts := 1; ; attempts++ {
+		err := probe(probeCtx)
+		if err == nil {
+			return nil
+		}
+
+		// a crashed entrypoint will never pass, so stop instead of waiting
+		// out the timeout
+		if state, stateErr := container.State(probeCtx); stateErr == nil && !state.Running {
+			return fmt.Errorf("container exited with code %d: %w", state.ExitCode, err)
+		}
+
+		select {
+		case <-probeCtx.Done():
+			if ctx.Err() != nil {
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+			}
+			return fmt.Errorf("not healthy after %d attempts in %s: %w", attempts, timeout, err)
+		case <-time.After(connectRetryDelay):
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package testing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// SetupHTTPServer starts an httptest.Server for handler that is closed when
+// the test ends
+func SetupHTTPServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// SetupTLSHTTPServer starts a TLS httptest.Server for handler and returns it
+// together with a client that trusts its certificate. Both are closed when
+// the test ends.
+func SetupTLSHTTPServer(t *testing.T, handler http.Handler) (*httptest.Server, *http.Client) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	return server, server.Client()
+}
+
+// maxBodySnippet limits how much of a response body failure messages print
+const maxBodySnippet = 512
+
+// ReadBody reads and closes the response body
+func ReadBody(t *testing.T, resp *http.Response) []byte {
+	t.Helper()
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response body: %v", err)
+	}
+	return body
+}
+
+// AssertHTTPStatus is a helper to assert a response has the expected status
+// code. On mismatch it reads the body and prints the beginning of it.
+func AssertHTTPStatus(t *testing.T, resp *http.Response, want int) {
+	t.Helper()
+
+	if resp.StatusCode != want {
+		body := ReadBody(t, resp)
+		if len(body) > maxBodySnippet {
+			body = append(body[:maxBodySnippet], "..."...)
+		}
+		failf(t, "AssertHTTPStatus", resp.StatusCode, want, "Got status %q, want %d %s\nbody: %s", resp.Status, want, http.StatusText(want), body)
+	}
+}
+
+// AssertBodyContains is a helper to assert the response body contains a
+// substring. The body is read and closed.
+func AssertBodyContains(t *testing.T, resp *http.Response, substr string) {
+	t.Helper()
+
+	body := string(ReadBody(t, resp))
+	if !strings.Contains(body, substr) {
+		failf(t, "AssertBodyContains", body, substr, "Expected response body to contain %q, got: %s", substr, body)
+	}
+}
@@ -0,0 +1,164 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// AssertJSONEq is a helper to assert two JSON documents are semantically
+// equal, ignoring whitespace and object key order
+func AssertJSONEq(t *testing.T, expected, actual string) {
+	t.Helper()
+
+	var want, got interface{}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("Expected value is not valid JSON: %v\n%s", err, expected)
+	}
+	if err := json.Unmarshal([]byte(actual), &got); err != nil {
+		t.Fatalf("Actual value is not valid JSON: %v\n%s", err, actual)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		failf(t, "AssertJSONEq", actual, expected, "JSON documents differ:\n%s",
+			unifiedDiff("expected", "actual", indentJSON(want), indentJSON(got)))
+	}
+}
+
+// AssertJSONArrayUnordered is a helper to assert two JSON arrays hold the same
+// elements in any order. Elements are compared like AssertJSONEq, counting
+// duplicates, and the unmatched elements of each side are reported.
+func AssertJSONArrayUnordered(t *testing.T, expected, actual string) {
+	t.Helper()
+
+	var want, got []interface{}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("Expected value is not a valid JSON array: %v\n%s", err, expected)
+	}
+	if err := json.Unmarshal([]byte(actual), &got); err != nil {
+		t.Fatalf("Actual value is not a valid JSON array: %v\n%s", err, actual)
+	}
+
+	matched := make([]bool, len(want))
+	var extra []string
+	for _, g := range got {
+		found := false
+		for i, w := range want {
+			if !matched[i] && reflect.DeepEqual(g, w) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			extra = append(extra, compactJSON(g))
+		}
+	}
+
+	var missing []string
+	for i, w := range want {
+		if !matched[i] {
+			missing = append(missing, compactJSON(w))
+		}
+	}
+
+	if len(missing) > 0 || len(extra) > 0 {
+		failf(t, "AssertJSONArrayUnordered", actual, expected,
+			"JSON arrays differ (got %d elements, want %d):\nmissing:\n\t%s\nextra:\n\t%s",
+			len(got), len(want), strings.Join(missing, "\n\t"), strings.Join(extra, "\n\t"))
+	}
+}
+
+// compactJSON re-marshals a decoded JSON value on a single line
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+// indentJSON re-marshals a decoded JSON value with stable indentation
+func indentJSON(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err.Error()
+	}
+	return string(data) + "\n"
+}
+
+// AssertJSONPath is a helper to assert the value at path in a JSON document.
+// path uses dots for object keys and brackets for array indexes, e.g.
+// "data.items[0].id". want is compared after a JSON round trip, so 1 matches
+// the decoded number 1 and structs match objects with the same fields.
+func AssertJSONPath(t *testing.T, body []byte, path string, want any) {
+	t.Helper()
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Body is not valid JSON: %v\n%s", err, body)
+	}
+
+	got, err := lookupJSONPath(doc, path)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Failed to encode expected value: %v", err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(wantJSON, &normalized); err != nil {
+		t.Fatalf("Failed to decode expected value: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, normalized) {
+		gotJSON, _ := json.Marshal(got)
+		failf(t, "AssertJSONPath", gotJSON, wantJSON, "JSON path %s: got %s, want %s", path, gotJSON, wantJSON)
+	}
+}
+
+// lookupJSONPath walks a decoded JSON document along a dotted/bracket path
+func lookupJSONPath(doc interface{}, path string) (interface{}, error) {
+	current := doc
+	walked := "$"
+	for _, part := range strings.Split(path, ".") {
+		key, indexes, _ := strings.Cut(part, "[")
+		if key != "" {
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("JSON path %s: %s is not an object", path, walked)
+			}
+			current, ok = obj[key]
+			if !ok {
+				return nil, fmt.Errorf("JSON path %s: %s has no key %q", path, walked, key)
+			}
+			walked += "." + key
+		}
+
+		if indexes == "" {
+			continue
+		}
+		// indexes is "0]" or "0][1]" after cutting the first bracket
+		for _, index := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+			i, err := strconv.Atoi(index)
+			if err != nil {
+				return nil, fmt.Errorf("JSON path %s: invalid index %q", path, index)
+			}
+			arr, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("JSON path %s: %s is not an array", path, walked)
+			}
+			if i < 0 || i >= len(arr) {
+				return nil, fmt.Errorf("JSON path %s: index %d out of range, %s has %d elements", path, i, walked, len(arr))
+			}
+			current = arr[i]
+			walked += fmt.Sprintf("[%d]", i)
+		}
+	}
+	return current, nil
+}
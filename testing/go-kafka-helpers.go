@@ -0,0 +1,140 @@
+package testing
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/redpanda"
+)
+
+const kafkaImage = "redpandadata/redpanda:v24.2.7"
+
+// KafkaContainer wraps a Kafka-compatible (Redpanda) test container
+type KafkaContainer struct {
+	Container testcontainers.Container
+	Brokers   []string
+}
+
+// SetupKafka creates a Redpanda test container speaking the Kafka protocol.
+// The broker advertises its host-mapped address, so Brokers can be used
+// directly as the bootstrap servers of any Kafka client.
+func SetupKafka(t *testing.T) *KafkaContainer {
+	t.Helper()
+
+	return SetupKafkaCtx(context.Background(), t)
+}
+
+// SetupKafkaCtx is SetupKafka with a caller-supplied context bounding
+// broker startup
+func SetupKafkaCtx(ctx context.Context, t *testing.T) *KafkaContainer {
+	t.Helper()
+
+	opts := []testcontainers.ContainerCustomizer{
+		redpanda.WithAutoCreateTopics(),
+		testcontainers.WithLabels(containerLabels(t.Name())),
+	}
+	if name := containerName(t.Name()); name != "" {
+		opts = append(opts, testcontainers.WithName(name))
+	}
+
+	container, err := redpanda.Run(ctx, kafkaImage, opts...)
+	if err != nil {
+		t.Fatalf("Failed to start Kafka container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	broker, err := container.KafkaSeedBroker(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get Kafka broker address: %v", err)
+	}
+
+	return &KafkaContainer{
+		Container: container,
+		Brokers:   []string{broker},
+	}
+}
+
+// NewWriter returns a producer for topic that is closed when the test ends
+func (k *KafkaContainer) NewWriter(t *testing.T, topic string) *kafka.Writer {
+	t.Helper()
+
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(k.Brokers...),
+		Topic:                  topic,
+		AllowAutoTopicCreation: true,
+	}
+
+	t.Cleanup(func() {
+		writer.Close()
+	})
+
+	return writer
+}
+
+// NewReader returns a consumer for topic in groupID that is closed when the
+// test ends. The reader starts from the oldest offset.
+func (k *KafkaContainer) NewReader(t *testing.T, topic, groupID string) *kafka.Reader {
+	t.Helper()
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     k.Brokers,
+		Topic:       topic,
+		GroupID:     groupID,
+		StartOffset: kafka.FirstOffset,
+	})
+
+	t.Cleanup(func() {
+		reader.Close()
+	})
+
+	return reader
+}
+
+// CreateTopic creates a Kafka topic with the given number of partitions,
+// asking the first reachable broker for the controller
+func CreateTopic(t *testing.T, brokers []string, topic string, partitions int) {
+	t.Helper()
+
+	if len(brokers) == 0 {
+		t.Fatalf("CreateTopic requires at least one broker")
+	}
+
+	var conn *kafka.Conn
+	var err error
+	for _, broker := range brokers {
+		if conn, err = kafka.Dial("tcp", broker); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatalf("Failed to connect to Kafka: %v", err)
+	}
+	defer conn.Close()
+
+	controller, err := conn.Controller()
+	if err != nil {
+		t.Fatalf("Failed to get Kafka controller: %v", err)
+	}
+
+	controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		t.Fatalf("Failed to connect to Kafka controller: %v", err)
+	}
+	defer controllerConn.Close()
+
+	err = controllerConn.CreateTopics(kafka.TopicConfig{
+		Topic:             topic,
+		NumPartitions:     partitions,
+		ReplicationFactor: 1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create topic %s: %v", topic, err)
+	}
+}
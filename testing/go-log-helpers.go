@@ -0,0 +1,145 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// logBuffer is a bytes.Buffer safe for concurrent handlers and readers
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := strings.TrimSuffix(b.buf.String(), "\n")
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// CaptureLogs returns a text-format logger that records every record at
+// debug level and above, and a function returning the lines logged so far.
+// The logger is also installed as slog.Default until the test ends, so code
+// logging through the package-level slog functions is captured too. slog.Default
+// and the log package's output are process-wide, so CaptureLogs must not be
+// used from parallel tests.
+func CaptureLogs(t *testing.T) (*slog.Logger, func() []string) {
+	t.Helper()
+
+	buf := &logBuffer{}
+	logger := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	// SetDefault also points the log package at the new handler, but setting
+	// the original default handler back leaves log alone, so restore it here
+	previous := slog.Default()
+	previousWriter, previousFlags := log.Writer(), log.Flags()
+	slog.SetDefault(logger)
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		log.SetOutput(previousWriter)
+		log.SetFlags(previousFlags)
+	})
+
+	return logger, buf.lines
+}
+
+// AssertLogContains is a helper to assert some captured log line contains a
+// substring
+func AssertLogContains(t *testing.T, lines []string, substr string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if strings.Contains(line, substr) {
+			return
+		}
+	}
+	failf(t, "AssertLogContains", nil, substr, "Expected a log line containing %q, got %d lines:\n%s", substr, len(lines), strings.Join(lines, "\n"))
+}
+
+// CaptureSQL installs a logger on db that records every statement gorm
+// executes, with parameters interpolated, and returns a function listing them
+// in execution order. The logger is shared by every session derived from db;
+// the previous one still receives all calls and is restored when the test
+// ends.
+func CaptureSQL(t *testing.T, db *gorm.DB) func() []string {
+	t.Helper()
+
+	previous := db.Logger
+	capture := &sqlCapture{next: previous, log: &sqlLog{}}
+	db.Logger = capture
+	t.Cleanup(func() {
+		db.Logger = previous
+	})
+
+	return capture.log.statements
+}
+
+// sqlLog collects statements from every copy of a sqlCapture
+type sqlLog struct {
+	mu    sync.Mutex
+	stmts []string
+}
+
+func (l *sqlLog) add(stmt string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stmts = append(l.stmts, stmt)
+}
+
+func (l *sqlLog) statements() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.stmts)
+}
+
+// sqlCapture is a gorm logger recording traced statements before delegating
+// to next
+type sqlCapture struct {
+	next logger.Interface
+	log  *sqlLog
+}
+
+// LogMode returns a copy, as sessions such as db.Debug() change the level
+// for themselves only
+func (c *sqlCapture) LogMode(level logger.LogLevel) logger.Interface {
+	return &sqlCapture{next: c.next.LogMode(level), log: c.log}
+}
+
+func (c *sqlCapture) Info(ctx context.Context, msg string, args ...interface{}) {
+	c.next.Info(ctx, msg, args...)
+}
+
+func (c *sqlCapture) Warn(ctx context.Context, msg string, args ...interface{}) {
+	c.next.Warn(ctx, msg, args...)
+}
+
+func (c *sqlCapture) Error(ctx context.Context, msg string, args ...interface{}) {
+	c.next.Error(ctx, msg, args...)
+}
+
+func (c *sqlCapture) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	c.log.add(sql)
+
+	c.next.Trace(ctx, begin, fc, err)
+}
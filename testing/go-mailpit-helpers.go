@@ -0,0 +1,181 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const mailpitImage = "axllent/mailpit:latest"
+
+// mailDeliveryTimeout bounds how long AssertEmailSent waits for a message,
+// since SMTP delivery to Mailpit completes asynchronously
+const mailDeliveryTimeout = 5 * time.Second
+
+// MailpitContainer wraps a Mailpit test container. Point the code under test
+// at SMTPHost:SMTPPort (no auth, no TLS) and inspect messages via APIURL.
+type MailpitContainer struct {
+	Container testcontainers.Container
+	SMTPHost  string
+	SMTPPort  int
+	APIURL    string
+}
+
+// MailpitAddress is a sender or recipient of a captured message
+type MailpitAddress struct {
+	Name    string `json:"Name"`
+	Address string `json:"Address"`
+}
+
+// MailpitMessage is the summary Mailpit returns for a captured message
+type MailpitMessage struct {
+	ID      string           `json:"ID"`
+	From    MailpitAddress   `json:"From"`
+	To      []MailpitAddress `json:"To"`
+	Subject string           `json:"Subject"`
+	Snippet string           `json:"Snippet"`
+}
+
+// SetupMailpit creates a Mailpit test container capturing every message
+// sent to its SMTP port
+func SetupMailpit(t *testing.T) *MailpitContainer {
+	t.Helper()
+
+	return SetupMailpitCtx(context.Background(), t)
+}
+
+// SetupMailpitCtx is SetupMailpit with a caller-supplied context bounding
+// container startup
+func SetupMailpitCtx(ctx context.Context, t *testing.T) *MailpitContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        mailpitImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"1025/tcp", "8025/tcp"},
+		WaitingFor: wait.ForHTTP("/readyz").
+			WithPort("8025/tcp").
+			WithStartupTimeout(60 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Mailpit container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	smtpPort, err := container.MappedPort(ctx, "1025")
+	if err != nil {
+		t.Fatalf("Failed to get container SMTP port: %v", err)
+	}
+
+	apiPort, err := container.MappedPort(ctx, "8025")
+	if err != nil {
+		t.Fatalf("Failed to get container API port: %v", err)
+	}
+
+	return &MailpitContainer{
+		Container: container,
+		SMTPHost:  host,
+		SMTPPort:  int(smtpPort.Num()),
+		APIURL:    fmt.Sprintf("http://%s:%s", host, apiPort.Port()),
+	}
+}
+
+// GetMessages returns the captured messages, newest first
+func (m *MailpitContainer) GetMessages(t *testing.T) []MailpitMessage {
+	t.Helper()
+
+	messages, err := m.messages()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return messages
+}
+
+// AssertEmailSent is a helper to assert a message to toAddress with a
+// subject containing subjectSubstr was captured. It waits up to 5s for
+// delivery.
+func (m *MailpitContainer) AssertEmailSent(t *testing.T, toAddress, subjectSubstr string) {
+	t.Helper()
+
+	var (
+		messages []MailpitMessage
+		err      error
+	)
+	_, _, ok := poll(mailDeliveryTimeout, WaitOptions{}, func() bool {
+		messages, err = m.messages()
+		return err == nil && findMessage(messages, toAddress, subjectSubstr)
+	})
+	if ok {
+		return
+	}
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var captured []string
+	for _, msg := range messages {
+		var to []string
+		for _, addr := range msg.To {
+			to = append(to, addr.Address)
+		}
+		captured = append(captured, fmt.Sprintf("to %s: %q", strings.Join(to, ", "), msg.Subject))
+	}
+	failf(t, "AssertEmailSent", nil, nil, "Expected an email to %s with subject containing %q, got %d messages:\n\t%s",
+		toAddress, subjectSubstr, len(messages), strings.Join(captured, "\n\t"))
+}
+
+// messages fetches the message list from the Mailpit API
+func (m *MailpitContainer) messages() ([]MailpitMessage, error) {
+	resp, err := http.Get(m.APIURL + "/api/v1/messages")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query Mailpit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to query Mailpit: %s", resp.Status)
+	}
+
+	var body struct {
+		Messages []MailpitMessage `json:"messages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("Failed to decode Mailpit messages: %w", err)
+	}
+	return body.Messages, nil
+}
+
+func findMessage(messages []MailpitMessage, toAddress, subjectSubstr string) bool {
+	for _, msg := range messages {
+		if !strings.Contains(msg.Subject, subjectSubstr) {
+			continue
+		}
+		for _, addr := range msg.To {
+			if strings.EqualFold(addr.Address, toAddress) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package testing
+
+import (
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// DBBackend names a database that ForEachDB can run against
+type DBBackend string
+
+const (
+	BackendPostgres  DBBackend = "postgres"
+	BackendMySQL     DBBackend = "mysql"
+	BackendCockroach DBBackend = "cockroach"
+	BackendSQLite    DBBackend = "sqlite"
+)
+
+// dbBackends opens each backend's database for a subtest, in the order
+// ForEachDB runs them
+var dbBackends = []struct {
+	name  DBBackend
+	setup func(t *testing.T) *gorm.DB
+}{
+	{BackendPostgres, func(t *testing.T) *gorm.DB { return SetupPostgres(t).DB }},
+	{BackendMySQL, func(t *testing.T) *gorm.DB { return SetupMySQL(t).DB }},
+	{BackendCockroach, func(t *testing.T) *gorm.DB { return SetupCockroach(t).DB }},
+	{BackendSQLite, func(t *testing.T) *gorm.DB { return SetupSQLite(t) }},
+}
+
+// optInDBBackends only run when named in WithBackends. SQLite misses
+// dialect features existing suites may rely on, and its driver needs cgo.
+var optInDBBackends = []DBBackend{BackendSQLite}
+
+// forEachDBConfig holds the settings applied by ForEachDBOption values
+type forEachDBConfig struct {
+	backends []DBBackend
+}
+
+// ForEachDBOption configures ForEachDB
+type ForEachDBOption func(*forEachDBConfig)
+
+// WithBackends restricts ForEachDB to the given backends (default: the
+// container backends, so BackendSQLite runs only when listed here)
+func WithBackends(backends ...DBBackend) ForEachDBOption {
+	return func(c *forEachDBConfig) {
+		c.backends = append(c.backends, backends...)
+	}
+}
+
+// ForEachDB runs fn as one subtest per backend, named after the backend,
+// each against a fresh database: a new container, or a new in-memory one for
+// SQLite. Running the same body on every dialect catches SQL that only works
+// on one of them, such as Postgres RETURNING.
+func ForEachDB(t *testing.T, fn func(t *testing.T, db *gorm.DB), opts ...ForEachDBOption) {
+	t.Helper()
+
+	var cfg forEachDBConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	for _, name := range cfg.backends {
+		if !knownDBBackend(name) {
+			t.Fatalf("Unknown database backend %q", name)
+		}
+	}
+
+	for _, backend := range dbBackends {
+		if len(cfg.backends) == 0 && slices.Contains(optInDBBackends, backend.name) {
+			continue
+		}
+		if len(cfg.backends) > 0 && !slices.Contains(cfg.backends, backend.name) {
+			continue
+		}
+
+		t.Run(string(backend.name), func(t *testing.T) {
+			fn(t, backend.setup(t))
+		})
+	}
+}
+
+func knownDBBackend(name DBBackend) bool {
+	for _, backend := range dbBackends {
+		if backend.name == name {
+			return true
+		}
+	}
+	return false
+}
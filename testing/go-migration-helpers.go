@@ -0,0 +1,95 @@
+package testing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// migrationsTable records applied versions, one row per version. It is kept
+// apart from golang-migrate's schema_migrations, which holds a single row
+// with the current version. TruncateAllTables never clears it, see
+// defaultTruncateExclusions.
+const migrationsTable = "test_schema_migrations"
+
+// RunMigrations applies the up-migrations in dir, named like golang-migrate's
+// (<version>_<name>.up.sql), in lexical order. Zero-pad the versions, as in
+// 000002_, or 10_ runs before 2_. Applied versions are recorded in
+// test_schema_migrations, so calling it again on the same database only
+// applies new files. Down-migrations are ignored.
+func RunMigrations(t *testing.T, db *gorm.DB, dir string) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		t.Fatalf("Invalid migrations directory %s: %v", dir, err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("No .up.sql migrations found in %s", dir)
+	}
+
+	err = db.Exec("CREATE TABLE IF NOT EXISTS " + migrationsTable +
+		" (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)").Error
+	if err != nil {
+		t.Fatalf("Failed to create %s table: %v", migrationsTable, err)
+	}
+
+	var applied []uint64
+	if err := db.Table(migrationsTable).Pluck("version", &applied).Error; err != nil {
+		t.Fatalf("Failed to read applied migrations: %v", err)
+	}
+
+	for _, file := range files {
+		version, err := migrationVersion(file)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+		if slices.Contains(applied, version) {
+			continue
+		}
+
+		if err := applyMigration(db, file, version); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+}
+
+// migrationVersion parses the numeric prefix of a migration file name
+func migrationVersion(file string) (uint64, error) {
+	prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+	version, err := strconv.ParseUint(prefix, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Migration file %s has no numeric version prefix", file)
+	}
+	return version, nil
+}
+
+// applyMigration executes a migration file and records its version in one
+// transaction. Databases without transactional DDL, such as MySQL, still
+// commit each statement as it runs.
+func applyMigration(db *gorm.DB, file string, version uint64) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("Failed to read migration %s: %w", file, err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for i, stmt := range splitSQLStatements(string(data)) {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("Failed to apply migration %s: statement %d: %w", file, i+1, err)
+			}
+		}
+
+		err := tx.Exec("INSERT INTO "+migrationsTable+" (version, dirty) VALUES (?, ?)", version, false).Error
+		if err != nil {
+			return fmt.Errorf("Failed to record migration %s: %w", file, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,105 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const minioImage = "minio/minio:latest"
+
+// MinIOContainer wraps a MinIO test container
+type MinIOContainer struct {
+	Container testcontainers.Container
+	Client    *minio.Client
+	Endpoint  string
+}
+
+// SetupMinIO creates a MinIO test container with the default
+// minioadmin/minioadmin credentials
+func SetupMinIO(t *testing.T) *MinIOContainer {
+	t.Helper()
+
+	return SetupMinIOCtx(context.Background(), t)
+}
+
+// SetupMinIOCtx is SetupMinIO with a caller-supplied context bounding
+// container startup
+func SetupMinIOCtx(ctx context.Context, t *testing.T) *MinIOContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        minioImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"9000/tcp"},
+		Env: map[string]string{
+			"MINIO_ROOT_USER":     "minioadmin",
+			"MINIO_ROOT_PASSWORD": "minioadmin",
+		},
+		Cmd:        []string{"server", "/data"},
+		WaitingFor: wait.ForHTTP("/minio/health/live").WithPort("9000/tcp"),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start MinIO container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "9000")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("%s:%s", host, port.Port())
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds: credentials.NewStaticV4("minioadmin", "minioadmin", ""),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create MinIO client: %v", err)
+	}
+
+	return &MinIOContainer{
+		Container: container,
+		Client:    client,
+		Endpoint:  endpoint,
+	}
+}
+
+// CreateBucket creates a bucket in MinIO
+func CreateBucket(t *testing.T, client *minio.Client, name string) {
+	t.Helper()
+
+	if err := client.MakeBucket(context.Background(), name, minio.MakeBucketOptions{}); err != nil {
+		t.Fatalf("Failed to create bucket %s: %v", name, err)
+	}
+}
+
+// UploadObject stores data under key in a MinIO bucket
+func UploadObject(t *testing.T, client *minio.Client, bucket, key string, data []byte) {
+	t.Helper()
+
+	_, err := client.PutObject(context.Background(), bucket, key, bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{})
+	if err != nil {
+		t.Fatalf("Failed to upload object %s/%s: %v", bucket, key, err)
+	}
+}
@@ -0,0 +1,98 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+const mongoImage = "mongo:7"
+
+// MongoContainer wraps a MongoDB test container
+type MongoContainer struct {
+	Container testcontainers.Container
+	Client    *mongo.Client
+	URI       string
+}
+
+// SetupMongo creates a MongoDB test container
+func SetupMongo(t *testing.T) *MongoContainer {
+	t.Helper()
+
+	return SetupMongoCtx(context.Background(), t)
+}
+
+// SetupMongoCtx is SetupMongo with a caller-supplied context bounding
+// container startup and the initial ping
+func SetupMongoCtx(ctx context.Context, t *testing.T) *MongoContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        mongoImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"27017/tcp"},
+		WaitingFor:   wait.ForLog("Waiting for connections"),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start MongoDB container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "27017")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	uri := fmt.Sprintf("mongodb://%s:%s", host, port.Port())
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+	})
+
+	// Test connection
+	err = waitForHealthy(ctx, container, connectTimeout, func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	})
+	if err != nil {
+		t.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+
+	return &MongoContainer{
+		Container: container,
+		Client:    client,
+		URI:       uri,
+	}
+}
+
+// DropDatabase drops a MongoDB database
+func DropDatabase(t *testing.T, client *mongo.Client, dbName string) {
+	t.Helper()
+
+	if err := client.Database(dbName).Drop(context.Background()); err != nil {
+		t.Fatalf("Failed to drop database %s: %v", dbName, err)
+	}
+}
@@ -0,0 +1,155 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+const mysqlImage = "mysql:8"
+
+// MySQLContainer wraps a MySQL test container
+type MySQLContainer struct {
+	Container testcontainers.Container
+	DB        *gorm.DB
+	DSN       string
+}
+
+// mysqlConfig holds the settings applied by MySQLOption values
+type mysqlConfig struct {
+	user     string
+	password string
+	dbname   string
+}
+
+// MySQLOption configures SetupMySQL
+type MySQLOption func(*mysqlConfig)
+
+// WithMySQLCredentials replaces the default user, password and database
+// (test/test/testdb). The root password is set to password as well; pass
+// "root" as user to connect as root only. MySQL rejects an empty password
+// for any user but root.
+func WithMySQLCredentials(user, password, dbname string) MySQLOption {
+	return func(c *mysqlConfig) {
+		c.user = user
+		c.password = password
+		c.dbname = dbname
+	}
+}
+
+// SetupMySQL creates a MySQL test container
+func SetupMySQL(t *testing.T, opts ...MySQLOption) *MySQLContainer {
+	t.Helper()
+
+	return SetupMySQLCtx(context.Background(), t, opts...)
+}
+
+// SetupMySQLCtx is SetupMySQL with a caller-supplied context bounding
+// container startup and the initial connection
+func SetupMySQLCtx(ctx context.Context, t *testing.T, opts ...MySQLOption) *MySQLContainer {
+	t.Helper()
+
+	cfg := mysqlConfig{
+		user:     defaultDBUser,
+		password: defaultDBPassword,
+		dbname:   defaultDBName,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	env := map[string]string{
+		"MYSQL_DATABASE": cfg.dbname,
+	}
+	if cfg.password == "" {
+		env["MYSQL_ALLOW_EMPTY_PASSWORD"] = "yes"
+	} else {
+		env["MYSQL_ROOT_PASSWORD"] = cfg.password
+	}
+	// the entrypoint refuses to create root again as a regular user
+	if cfg.user != "root" {
+		env["MYSQL_USER"] = cfg.user
+		env["MYSQL_PASSWORD"] = cfg.password
+	}
+
+	req := testcontainers.ContainerRequest{
+		Image:        mysqlImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"3306/tcp"},
+		Env:          env,
+		// The temporary init server also logs "ready for connections" but
+		// never listens on TCP, so wait for the port as well.
+		WaitingFor: wait.ForAll(
+			wait.ForLog("ready for connections").WithOccurrence(2),
+			wait.ForListeningPort("3306/tcp"),
+		).WithDeadline(90 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start MySQL container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "3306")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	dsnConfig := mysqldriver.NewConfig()
+	dsnConfig.User = cfg.user
+	dsnConfig.Passwd = cfg.password
+	dsnConfig.Net = "tcp"
+	dsnConfig.Addr = fmt.Sprintf("%s:%s", host, port.Port())
+	dsnConfig.DBName = cfg.dbname
+	dsnConfig.Params = map[string]string{"charset": "utf8mb4"}
+	dsnConfig.ParseTime = true
+	dsnConfig.Loc = time.UTC
+	dsn := dsnConfig.FormatDSN()
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get database handle: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sqlDB.Close()
+	})
+
+	err = waitForHealthy(ctx, container, connectTimeout, func(ctx context.Context) error {
+		return sqlDB.PingContext(ctx)
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	return &MySQLContainer{
+		Container: container,
+		DB:        db,
+		DSN:       dsn,
+	}
+}
@@ -0,0 +1,135 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const natsImage = "nats:latest"
+
+// NATSContainer wraps a NATS test container
+type NATSContainer struct {
+	Container testcontainers.Container
+	Conn      *nats.Conn
+	URL       string
+}
+
+// natsConfig holds the settings applied by NATSOption values
+type natsConfig struct {
+	jetStream bool
+}
+
+// NATSOption configures SetupNATS
+type NATSOption func(*natsConfig)
+
+// WithJetStream enables JetStream persistence on the server
+func WithJetStream() NATSOption {
+	return func(c *natsConfig) {
+		c.jetStream = true
+	}
+}
+
+// SetupNATS creates a NATS test container
+func SetupNATS(t *testing.T, opts ...NATSOption) *NATSContainer {
+	t.Helper()
+
+	return SetupNATSCtx(context.Background(), t, opts...)
+}
+
+// SetupNATSCtx is SetupNATS with a caller-supplied context bounding
+// container startup and the initial connection
+func SetupNATSCtx(ctx context.Context, t *testing.T, opts ...NATSOption) *NATSContainer {
+	t.Helper()
+
+	var cfg natsConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	cmd := []string{"--http_port", "8222"}
+	if cfg.jetStream {
+		cmd = append(cmd, "--jetstream")
+	}
+
+	req := testcontainers.ContainerRequest{
+		Image:        natsImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"4222/tcp", "8222/tcp"},
+		Cmd:          cmd,
+		// /healthz reports ok once the server, and JetStream if enabled, is ready
+		WaitingFor: wait.ForHTTP("/healthz").
+			WithPort("8222/tcp").
+			WithStartupTimeout(60 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start NATS container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "4222")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	url := fmt.Sprintf("nats://%s:%s", host, port.Port())
+	var conn *nats.Conn
+	err = waitForHealthy(ctx, container, connectTimeout, func(ctx context.Context) error {
+		var err error
+		conn, err = nats.Connect(url)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect to NATS: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return &NATSContainer{
+		Container: container,
+		Conn:      conn,
+		URL:       url,
+	}
+}
+
+// CreateStream creates a JetStream stream capturing the given subjects. The
+// server must be started WithJetStream.
+func CreateStream(t *testing.T, conn *nats.Conn, name string, subjects ...string) jetstream.Stream {
+	t.Helper()
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatalf("Failed to create JetStream context: %v", err)
+	}
+
+	stream, err := js.CreateStream(context.Background(), jetstream.StreamConfig{
+		Name:     name,
+		Subjects: subjects,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create stream %s: %v", name, err)
+	}
+	return stream
+}
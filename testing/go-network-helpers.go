@@ -0,0 +1,46 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+)
+
+// CreateNetwork creates a Docker bridge network for the test. Containers
+// joined to it with WithNetwork or WithRedisNetwork resolve each other by
+// alias. The network is removed when the test ends, after the containers
+// registered later are terminated.
+func CreateNetwork(t *testing.T) *testcontainers.DockerNetwork {
+	t.Helper()
+
+	nw, err := network.New(context.Background(), network.WithLabels(containerLabels(t.Name())))
+	if err != nil {
+		t.Fatalf("Failed to create Docker network: %v", err)
+	}
+
+	t.Cleanup(func() {
+		nw.Remove(context.Background())
+	})
+
+	return nw
+}
+
+// networkConfig attaches a container to a network created by CreateNetwork
+type networkConfig struct {
+	name    string
+	aliases []string
+}
+
+// apply adds the network and its aliases to req; the zero value does nothing
+func (n networkConfig) apply(req *testcontainers.ContainerRequest) {
+	if n.name == "" {
+		return
+	}
+	req.Networks = append(req.Networks, n.name)
+	if req.NetworkAliases == nil {
+		req.NetworkAliases = make(map[string][]string)
+	}
+	req.NetworkAliases[n.name] = append(req.NetworkAliases[n.name], n.aliases...)
+}
@@ -0,0 +1,262 @@
+package testing
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// jaegerImage receives OTLP and serves what it captured from an in-memory
+// store through its query API
+const jaegerImage = "jaegertracing/all-in-one:1.62.0"
+
+// spanDeliveryTimeout bounds how long WaitForSpans waits, since the
+// collector stores exported spans asynchronously
+const spanDeliveryTimeout = 5 * time.Second
+
+// OTLPCollectorContainer wraps a Jaeger all-in-one test container accepting
+// OTLP traces. Point the exporter at GRPCEndpoint (otlptracegrpc) or
+// HTTPEndpoint (otlptracehttp), both host:port without TLS.
+type OTLPCollectorContainer struct {
+	Container    testcontainers.Container
+	GRPCEndpoint string
+	HTTPEndpoint string
+	QueryURL     string
+}
+
+// Span is a captured span. Attribute values keep their JSON types, so
+// integers decode as float64.
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Service      string
+	Attributes   map[string]any
+	StartTime    time.Time
+	Duration     time.Duration
+}
+
+// SetupOTLPCollector creates a collector container capturing every span
+// exported to it
+func SetupOTLPCollector(t *testing.T) *OTLPCollectorContainer {
+	t.Helper()
+
+	return SetupOTLPCollectorCtx(context.Background(), t)
+}
+
+// SetupOTLPCollectorCtx is SetupOTLPCollector with a caller-supplied context
+// bounding container startup
+func SetupOTLPCollectorCtx(ctx context.Context, t *testing.T) *OTLPCollectorContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        jaegerImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"4317/tcp", "4318/tcp", "16686/tcp", "14269/tcp"},
+		Env: map[string]string{
+			"COLLECTOR_OTLP_ENABLED": "true",
+		},
+		// the admin port answers once every component, including the OTLP
+		// receivers, has started
+		WaitingFor: wait.ForHTTP("/").
+			WithPort("14269/tcp").
+			WithStartupTimeout(60 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start OTLP collector container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	grpcPort, err := container.MappedPort(ctx, "4317")
+	if err != nil {
+		t.Fatalf("Failed to get container OTLP gRPC port: %v", err)
+	}
+
+	httpPort, err := container.MappedPort(ctx, "4318")
+	if err != nil {
+		t.Fatalf("Failed to get container OTLP HTTP port: %v", err)
+	}
+
+	queryPort, err := container.MappedPort(ctx, "16686")
+	if err != nil {
+		t.Fatalf("Failed to get container query port: %v", err)
+	}
+
+	return &OTLPCollectorContainer{
+		Container:    container,
+		GRPCEndpoint: fmt.Sprintf("%s:%s", host, grpcPort.Port()),
+		HTTPEndpoint: fmt.Sprintf("%s:%s", host, httpPort.Port()),
+		QueryURL:     fmt.Sprintf("http://%s:%s", host, queryPort.Port()),
+	}
+}
+
+// GetSpans returns every span captured so far from all services, ordered by
+// start time. Flush the TracerProvider first; use WaitForSpans when the
+// spans may still be in flight.
+func (c *OTLPCollectorContainer) GetSpans(t *testing.T) []Span {
+	t.Helper()
+
+	spans, err := c.spans()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return spans
+}
+
+// WaitForSpans waits up to 5s until at least n spans are captured and
+// returns them, ordered by start time
+func (c *OTLPCollectorContainer) WaitForSpans(t *testing.T, n int) []Span {
+	t.Helper()
+
+	var (
+		spans []Span
+		err   error
+	)
+	_, _, ok := poll(spanDeliveryTimeout, WaitOptions{}, func() bool {
+		spans, err = c.spans()
+		return err == nil && len(spans) >= n
+	})
+	if ok {
+		return spans
+	}
+	if err != nil {
+		failf(t, "WaitForSpans", nil, n, "%v", err)
+	}
+
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name
+	}
+	failf(t, "WaitForSpans", len(spans), n, "Expected at least %d spans within %s, got %d: %v", n, spanDeliveryTimeout, len(spans), names)
+	return nil
+}
+
+// jaegerInternalServices are the names Jaeger records its own spans under
+var jaegerInternalServices = []string{"jaeger-all-in-one", "jaeger-query"}
+
+// jaegerTrace is a trace as returned by the Jaeger query API
+type jaegerTrace struct {
+	Spans []struct {
+		TraceID       string `json:"traceID"`
+		SpanID        string `json:"spanID"`
+		OperationName string `json:"operationName"`
+		References    []struct {
+			RefType string `json:"refType"`
+			SpanID  string `json:"spanID"`
+		} `json:"references"`
+		StartTime int64 `json:"startTime"`
+		Duration  int64 `json:"duration"`
+		Tags      []struct {
+			Key   string `json:"key"`
+			Value any    `json:"value"`
+		} `json:"tags"`
+		ProcessID string `json:"processID"`
+	} `json:"spans"`
+	Processes map[string]struct {
+		ServiceName string `json:"serviceName"`
+	} `json:"processes"`
+}
+
+// spans fetches the traces of every known service from the query API. A
+// trace crossing services is returned for each of them, so spans are kept
+// once per trace and span ID.
+func (c *OTLPCollectorContainer) spans() ([]Span, error) {
+	var services []string
+	if err := c.query("/api/services", &services); err != nil {
+		return nil, err
+	}
+
+	type spanKey struct{ traceID, spanID string }
+	seen := make(map[spanKey]bool)
+
+	var spans []Span
+	for _, service := range services {
+		if slices.Contains(jaegerInternalServices, service) {
+			continue
+		}
+
+		var traces []jaegerTrace
+		if err := c.query("/api/traces?limit=1000&service="+url.QueryEscape(service), &traces); err != nil {
+			return nil, err
+		}
+
+		for _, trace := range traces {
+			for _, s := range trace.Spans {
+				key := spanKey{s.TraceID, s.SpanID}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				span := Span{
+					TraceID:    s.TraceID,
+					SpanID:     s.SpanID,
+					Name:       s.OperationName,
+					Service:    trace.Processes[s.ProcessID].ServiceName,
+					Attributes: make(map[string]any, len(s.Tags)),
+					StartTime:  time.UnixMicro(s.StartTime),
+					Duration:   time.Duration(s.Duration) * time.Microsecond,
+				}
+				for _, ref := range s.References {
+					if ref.RefType == "CHILD_OF" {
+						span.ParentSpanID = ref.SpanID
+					}
+				}
+				for _, tag := range s.Tags {
+					span.Attributes[tag.Key] = tag.Value
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+
+	slices.SortFunc(spans, func(a, b Span) int {
+		return cmp.Compare(a.StartTime.UnixNano(), b.StartTime.UnixNano())
+	})
+	return spans, nil
+}
+
+// query decodes the data field of a Jaeger query API response into dest
+func (c *OTLPCollectorContainer) query(path string, dest any) error {
+	resp, err := http.Get(c.QueryURL + path)
+	if err != nil {
+		return fmt.Errorf("Failed to query OTLP collector: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Failed to query OTLP collector: %s", resp.Status)
+	}
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: dest}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("Failed to decode OTLP collector response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const rabbitMQImage = "rabbitmq:3-management-alpine"
+
+// RabbitMQContainer wraps a RabbitMQ test container
+type RabbitMQContainer struct {
+	Container testcontainers.Container
+	Conn      *amqp.Connection
+	URI       string
+}
+
+// SetupRabbitMQ creates a RabbitMQ test container
+func SetupRabbitMQ(t *testing.T) *RabbitMQContainer {
+	t.Helper()
+
+	return SetupRabbitMQCtx(context.Background(), t)
+}
+
+// SetupRabbitMQCtx is SetupRabbitMQ with a caller-supplied context
+// bounding container startup and the AMQP dial
+func SetupRabbitMQCtx(ctx context.Context, t *testing.T) *RabbitMQContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        rabbitMQImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"5672/tcp", "15672/tcp"},
+		Env: map[string]string{
+			"RABBITMQ_DEFAULT_USER": "test",
+			"RABBITMQ_DEFAULT_PASS": "test",
+		},
+		WaitingFor: wait.ForLog("Server startup complete").
+			WithStartupTimeout(60 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start RabbitMQ container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "5672")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	uri := fmt.Sprintf("amqp://test:test@%s:%s/", host, port.Port())
+	var conn *amqp.Connection
+	err = waitForHealthy(ctx, container, connectTimeout, func(ctx context.Context) error {
+		var err error
+		conn, err = amqp.DialConfig(uri, amqp.Config{
+			Heartbeat: 10 * time.Second,
+			Locale:    "en_US",
+			Dial: func(network, addr string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect to RabbitMQ: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return &RabbitMQContainer{
+		Container: container,
+		Conn:      conn,
+		URI:       uri,
+	}
+}
+
+// PurgeQueue removes all pending messages from a RabbitMQ queue
+func PurgeQueue(t *testing.T, ch *amqp.Channel, queueName string) {
+	t.Helper()
+
+	if _, err := ch.QueuePurge(queueName, false); err != nil {
+		t.Fatalf("Failed to purge queue %s: %v", queueName, err)
+	}
+}
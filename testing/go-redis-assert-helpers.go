@@ -0,0 +1,57 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AssertRedisKeyExists is a helper to assert a key is present
+func AssertRedisKeyExists(t *testing.T, client *redis.Client, key string) {
+	t.Helper()
+
+	n, err := client.Exists(context.Background(), key).Result()
+	if err != nil {
+		t.Fatalf("Failed to check Redis key %s: %v", key, err)
+	}
+	if n == 0 {
+		failf(t, "AssertRedisKeyExists", "absent", "present", "Expected Redis key %s to exist", key)
+	}
+}
+
+// AssertRedisKeyAbsent is a helper to assert a key is missing or expired
+func AssertRedisKeyAbsent(t *testing.T, client *redis.Client, key string) {
+	t.Helper()
+
+	n, err := client.Exists(context.Background(), key).Result()
+	if err != nil {
+		t.Fatalf("Failed to check Redis key %s: %v", key, err)
+	}
+	if n != 0 {
+		failf(t, "AssertRedisKeyAbsent", "present", "absent", "Expected Redis key %s to be absent", key)
+	}
+}
+
+// AssertRedisTTL is a helper to assert a key expires in want ± delta. Redis
+// counts down from the moment the key was set, so leave delta room for the
+// time the test has taken since.
+func AssertRedisTTL(t *testing.T, client *redis.Client, key string, want, delta time.Duration) {
+	t.Helper()
+
+	ttl, err := client.TTL(context.Background(), key).Result()
+	if err != nil {
+		t.Fatalf("Failed to get TTL of Redis key %s: %v", key, err)
+	}
+
+	// TTL reports -2 for a missing key and -1 for a key without expiry
+	switch {
+	case ttl == -2:
+		failf(t, "AssertRedisTTL", "absent", want, "Key %s does not exist, want TTL %s±%s", key, want, delta)
+	case ttl == -1:
+		failf(t, "AssertRedisTTL", "no expiry", want, "Key %s has no TTL, want %s±%s", key, want, delta)
+	case ttl < want-delta || ttl > want+delta:
+		failf(t, "AssertRedisTTL", ttl, want, "Key %s has TTL %s, want %s±%s", key, ttl, want, delta)
+	}
+}
@@ -0,0 +1,138 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	defaultRedisClusterImage = "grokzen/redis-cluster:7.0.10"
+	// redisClusterFirstPort and redisClusterNodes match the image defaults:
+	// three masters and three replicas on ports 7000-7005
+	redisClusterFirstPort = 7000
+	redisClusterNodes     = 6
+	// redisClusterReadyTimeout bounds the wait for cluster_state:ok, which
+	// the image reaches a few seconds after the nodes start listening
+	redisClusterReadyTimeout = 60 * time.Second
+)
+
+// RedisClusterContainer wraps a Redis Cluster test container. Addrs holds
+// the host-mapped address of every node.
+type RedisClusterContainer struct {
+	Container testcontainers.Container
+	Client    *redis.ClusterClient
+	Addrs     []string
+}
+
+// SetupRedisCluster creates a Redis Cluster test container with three
+// masters and three replicas. The client follows MOVED and ASK redirects, so
+// tests exercise the same slot routing as production.
+func SetupRedisCluster(t *testing.T) *RedisClusterContainer {
+	t.Helper()
+
+	return SetupRedisClusterCtx(context.Background(), t)
+}
+
+// SetupRedisClusterCtx is SetupRedisCluster with a caller-supplied context
+// bounding container startup and the wait for the cluster to become ready
+func SetupRedisClusterCtx(ctx context.Context, t *testing.T) *RedisClusterContainer {
+	t.Helper()
+
+	var exposed []string
+	for i := 0; i < redisClusterNodes; i++ {
+		exposed = append(exposed, fmt.Sprintf("%d/tcp", redisClusterFirstPort+i))
+	}
+
+	req := testcontainers.ContainerRequest{
+		Image:        defaultRedisClusterImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: exposed,
+		Env: map[string]string{
+			// Bind on all interfaces so the mapped ports are reachable
+			"IP":           "0.0.0.0",
+			"INITIAL_PORT": fmt.Sprint(redisClusterFirstPort),
+		},
+		WaitingFor: wait.ForListeningPort(fmt.Sprintf("%d/tcp", redisClusterFirstPort+redisClusterNodes-1)).
+			WithStartupTimeout(60 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Redis Cluster container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	// Nodes announce their container-internal addresses in CLUSTER SLOTS and
+	// MOVED replies; remap each internal port to its host-mapped address
+	mapped := make(map[string]string, redisClusterNodes)
+	var addrs []string
+	for i := 0; i < redisClusterNodes; i++ {
+		internal := fmt.Sprint(redisClusterFirstPort + i)
+		port, err := container.MappedPort(ctx, internal)
+		if err != nil {
+			t.Fatalf("Failed to get container port %s: %v", internal, err)
+		}
+		addr := net.JoinHostPort(host, port.Port())
+		mapped[internal] = addr
+		addrs = append(addrs, addr)
+	}
+
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs: addrs,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if _, port, err := net.SplitHostPort(addr); err == nil {
+				if hostAddr, ok := mapped[port]; ok {
+					addr = hostAddr
+				}
+			}
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	})
+	t.Cleanup(func() {
+		client.Close()
+	})
+
+	// Wait for slot assignment to finish before handing out the client
+	err = waitForHealthy(ctx, container, redisClusterReadyTimeout, func(ctx context.Context) error {
+		info, err := client.ClusterInfo(ctx).Result()
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(info, "cluster_state:ok") {
+			return errors.New("cluster_state is not ok")
+		}
+		client.ReloadState(ctx)
+		return client.Ping(ctx).Err()
+	})
+	if err != nil {
+		t.Fatalf("Failed to wait for Redis Cluster: %v", err)
+	}
+
+	return &RedisClusterContainer{
+		Container: container,
+		Client:    client,
+		Addrs:     addrs,
+	}
+}
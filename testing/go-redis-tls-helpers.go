@@ -0,0 +1,216 @@
+package testing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// RedisTLSContainer wraps a Redis test container that only accepts TLS
+// connections. CACert and TLSConfig let tests build their own clients.
+type RedisTLSContainer struct {
+	RedisContainer
+	CACert    []byte
+	TLSConfig *tls.Config
+}
+
+// SetupRedisTLS creates a Redis test container serving TLS with a freshly
+// generated CA and server certificate. It accepts the same options as
+// SetupRedis; the client's TLSConfig is always set to trust the generated CA.
+func SetupRedisTLS(t *testing.T, opts ...RedisOption) *RedisTLSContainer {
+	t.Helper()
+
+	return SetupRedisTLSCtx(context.Background(), t, opts...)
+}
+
+// SetupRedisTLSCtx is SetupRedisTLS with a caller-supplied context
+// bounding container startup and the initial ping
+func SetupRedisTLSCtx(ctx context.Context, t *testing.T, opts ...RedisOption) *RedisTLSContainer {
+	t.Helper()
+
+	cfg := newRedisConfig(opts)
+
+	certs, err := generateTestCertificates()
+	if err != nil {
+		t.Fatalf("Failed to generate TLS certificates: %v", err)
+	}
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"ca.crt":     certs.caCert,
+		"server.crt": certs.serverCert,
+		"server.key": certs.serverKey,
+	}
+	var containerFiles []testcontainers.ContainerFile
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		containerFiles = append(containerFiles, testcontainers.ContainerFile{
+			HostFilePath:      path,
+			ContainerFilePath: "/tls/" + name,
+			FileMode:          0o644,
+		})
+	}
+
+	req := testcontainers.ContainerRequest{
+		Image:        cfg.image,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"6379/tcp"},
+		Files:        containerFiles,
+		Cmd: []string{
+			"redis-server",
+			"--port", "0",
+			"--tls-port", "6379",
+			"--tls-cert-file", "/tls/server.crt",
+			"--tls-key-file", "/tls/server.key",
+			"--tls-ca-cert-file", "/tls/ca.crt",
+			"--tls-auth-clients", "no",
+		},
+		WaitingFor: wait.ForLog(cfg.waitLog),
+	}
+	cfg.network.apply(&req)
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Redis container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+	if cfg.logsOnFailure {
+		dumpLogsOnFailure(t, container)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "6379")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(certs.caCert)
+	tlsConfig := &tls.Config{
+		RootCAs: pool,
+		// The certificate is issued for localhost, whatever the Docker host is
+		ServerName: "localhost",
+		MinVersion: tls.VersionTLS12,
+	}
+
+	addr := fmt.Sprintf("%s:%s", host, port.Port())
+	clientOpts := &redis.Options{}
+	for _, fn := range cfg.clientOptions {
+		fn(clientOpts)
+	}
+	clientOpts.Addr = addr
+	clientOpts.TLSConfig = tlsConfig.Clone()
+	client := redis.NewClient(clientOpts)
+	t.Cleanup(func() {
+		client.Close()
+	})
+
+	// Test connection
+	err = waitForHealthy(ctx, container, connectTimeout, func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect to Redis: %v", err)
+	}
+
+	return &RedisTLSContainer{
+		RedisContainer: RedisContainer{
+			Container: container,
+			Client:    client,
+			Addr:      addr,
+		},
+		CACert:    certs.caCert,
+		TLSConfig: tlsConfig,
+	}
+}
+
+// testCertificates holds PEM-encoded certificates for TLS-enabled containers
+type testCertificates struct {
+	caCert     []byte
+	serverCert []byte
+	serverKey  []byte
+}
+
+// generateTestCertificates creates a throwaway CA and a server certificate
+// for localhost signed by it
+func generateTestCertificates() (*testCertificates, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "modsynth test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	serverDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caTemplate, &serverKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(serverKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &testCertificates{
+		caCert:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		serverCert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverDER}),
+		serverKey:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
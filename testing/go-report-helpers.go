@@ -0,0 +1,100 @@
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// structuredOutputEnv enables a JSON line on stdout for every assertion
+// failure, for CI tooling that aggregates failures across suites
+const structuredOutputEnv = "TEST_STRUCTURED_OUTPUT"
+
+// assertionFailure is the record written for each failure when structured
+// output is enabled. Got and Want are %v renderings, omitted when an
+// assertion has no single value to report.
+type assertionFailure struct {
+	Assertion string `json:"assertion"`
+	Test      string `json:"test"`
+	Got       string `json:"got,omitempty"`
+	Want      string `json:"want,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Message   string `json:"message"`
+}
+
+// failf fails the test with the formatted message. When TEST_STRUCTURED_OUTPUT=1
+// it first writes the failure as a single JSON line to stdout. Pass nil for
+// got or want when they do not apply.
+func failf(t *testing.T, assertion string, got, want any, format string, args ...any) {
+	t.Helper()
+
+	t.Fatal(reportFailure(t, assertion, got, want, format, args...))
+}
+
+// errorf is failf for cleanup checks, which mark the test failed without
+// stopping it so other cleanups still run
+func errorf(t *testing.T, assertion string, got, want any, format string, args ...any) {
+	t.Helper()
+
+	t.Error(reportFailure(t, assertion, got, want, format, args...))
+}
+
+// reportFailure formats the failure message and writes its structured record
+// when TEST_STRUCTURED_OUTPUT=1
+func reportFailure(t *testing.T, assertion string, got, want any, format string, args ...any) string {
+	msg := fmt.Sprintf(format, args...)
+	if os.Getenv(structuredOutputEnv) != "1" {
+		return msg
+	}
+
+	record := assertionFailure{
+		Assertion: assertion,
+		Test:      t.Name(),
+		Location:  callerLocation(),
+		Message:   msg,
+	}
+	if got != nil {
+		record.Got = fmt.Sprint(got)
+	}
+	if want != nil {
+		record.Want = fmt.Sprint(want)
+	}
+	if data, err := json.Marshal(record); err == nil {
+		fmt.Fprintln(os.Stdout, string(data))
+	}
+	return msg
+}
+
+// callerLocation returns the file:line of the first frame outside this
+// package and the testing and runtime packages, i.e. the assertion call in the
+// test. It is empty for checks run from t.Cleanup, which have no call site.
+func callerLocation() string {
+	// this function's own name gives the package prefix to skip
+	self, _, _, _ := runtime.Caller(0)
+	prefix := strings.TrimSuffix(runtime.FuncForPC(self).Name(), "callerLocation")
+
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !hasAnyPrefix(frame.Function, prefix, "testing.", "runtime.") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// hasAnyPrefix reports whether s starts with any of prefixes
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package testing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// SeedSQL executes the statements in the given .sql files against db. Paths
+// may be glob patterns such as "testdata/seeds/*.sql"; files are applied in
+// argument order, and matches of a single pattern in lexical order.
+func SeedSQL(t *testing.T, db *gorm.DB, paths ...string) {
+	t.Helper()
+
+	files, err := expandSQLPaths(paths)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if err := execSQLFiles(db, files); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+// expandSQLPaths resolves glob patterns to file names, failing when a
+// pattern matches nothing so typos don't silently skip seeding
+func expandSQLPaths(patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid SQL file pattern %s: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("No SQL files match %s", pattern)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// execSQLFiles executes every statement of every file in order
+func execSQLFiles(db *gorm.DB, files []string) error {
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("Failed to read SQL file %s: %w", file, err)
+		}
+
+		for i, stmt := range splitSQLStatements(string(data)) {
+			if err := db.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("Failed to execute statement %d of %s: %w", i+1, file, err)
+			}
+		}
+	}
+	return nil
+}
+
+// splitSQLStatements splits a SQL script on semicolons, ignoring those inside
+// quoted strings, identifiers, comments and dollar-quoted bodies. Statements
+// consisting only of whitespace and comments are dropped.
+func splitSQLStatements(script string) []string {
+	var (
+		stmts      []string
+		current    strings.Builder
+		hasContent bool
+	)
+
+	flush := func() {
+		if hasContent {
+			stmts = append(stmts, strings.TrimSpace(current.String()))
+		}
+		current.Reset()
+		hasContent = false
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		rest := script[i:]
+
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			current.WriteString(rest[:end])
+			i += end - 1
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += 4
+			}
+			current.WriteString(rest[:end])
+			i += end - 1
+		case c == '\'' || c == '"':
+			end := closingQuote(rest, c)
+			current.WriteString(rest[:end])
+			hasContent = true
+			i += end - 1
+		case c == '$':
+			if tag := dollarQuoteTag(rest); tag != "" {
+				end := strings.Index(rest[len(tag):], tag)
+				if end < 0 {
+					end = len(rest)
+				} else {
+					end += 2 * len(tag)
+				}
+				current.WriteString(rest[:end])
+				hasContent = true
+				i += end - 1
+				continue
+			}
+			current.WriteByte(c)
+			hasContent = true
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				hasContent = true
+			}
+		}
+	}
+	flush()
+
+	return stmts
+}
+
+// closingQuote returns the length of the quoted section at the start of s,
+// treating a doubled quote as an escape
+func closingQuote(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		if s[i] == quote {
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// dollarQuoteTag returns the opening tag ($$ or $name$) at the start of s, or
+// an empty string if s does not start a dollar-quoted section
+func dollarQuoteTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+		isIdent := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 1 && c >= '0' && c <= '9')
+		if !isIdent {
+			return ""
+		}
+	}
+	return ""
+}
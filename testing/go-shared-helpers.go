@@ -0,0 +1,233 @@
+package testing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var (
+	sharedPostgresOnce sync.Once
+	sharedPostgres     *PostgresContainer
+	sharedPostgresErr  error
+
+	sharedMu          sync.Mutex
+	sharedTerminators []sharedTerminator
+)
+
+// sharedTerminator stops a shared container, keyed by the container so it
+// can be unregistered when a test discards it early
+type sharedTerminator struct {
+	key       any
+	terminate func()
+}
+
+// SetupSharedPostgres returns a PostgreSQL container shared by every test in
+// the test binary. The container is started on first use and kept running
+// until TerminateSharedContainers is called, so the startup cost is paid once.
+//
+// Tests sharing the container also share its database and are NOT isolated
+// from each other: truncate the tables a test uses before it runs (see
+// TruncateTables) and avoid t.Parallel for tests touching the same tables.
+func SetupSharedPostgres(t *testing.T) *PostgresContainer {
+	t.Helper()
+
+	sharedPostgresOnce.Do(func() {
+		sharedPostgres, sharedPostgresErr = startPostgres(context.Background(), postgresConfig{
+			image:    defaultPostgresImage,
+			testName: sharedTestName,
+		})
+		if sharedPostgresErr == nil {
+			registerShared(sharedPostgres, sharedPostgres.terminate)
+		}
+	})
+	if sharedPostgresErr != nil {
+		t.Fatalf("Failed to set up shared PostgreSQL container: %v", sharedPostgresErr)
+	}
+
+	return sharedPostgres
+}
+
+// defaultPostgresPoolSize is the pool size used when TEST_PG_POOL_SIZE is unset
+const defaultPostgresPoolSize = 4
+
+var (
+	postgresPoolOnce sync.Once
+	postgresPool     *containerPool
+)
+
+// containerPool bounds how many pooled PostgreSQL containers run at once
+type containerPool struct {
+	idle  chan *PostgresContainer
+	slots chan struct{}
+}
+
+// SetupPostgresFromPool checks a PostgreSQL container out of a pool shared by
+// the test binary, blocking until one is free. At most TEST_PG_POOL_SIZE
+// containers (default 4) run at once, which keeps t.Parallel suites from
+// exhausting Docker. When the test ends every table in the public schema is
+// truncated and the container goes back to the pool.
+//
+// Pooled containers are stopped by TerminateSharedContainers.
+func SetupPostgresFromPool(t *testing.T) *PostgresContainer {
+	t.Helper()
+
+	postgresPoolOnce.Do(func() {
+		size := defaultPostgresPoolSize
+		if n, err := strconv.Atoi(os.Getenv("TEST_PG_POOL_SIZE")); err == nil && n > 0 {
+			size = n
+		}
+		postgresPool = &containerPool{
+			idle:  make(chan *PostgresContainer, size),
+			slots: make(chan struct{}, size),
+		}
+	})
+
+	pg := postgresPool.checkout(t)
+
+	t.Cleanup(func() {
+		postgresPool.checkin(pg)
+	})
+
+	return pg
+}
+
+// checkout returns an idle container, starting a new one if the pool has
+// room, or blocks until another test returns one
+func (p *containerPool) checkout(t *testing.T) *PostgresContainer {
+	t.Helper()
+
+	select {
+	case pg := <-p.idle:
+		return pg
+	default:
+	}
+
+	select {
+	case pg := <-p.idle:
+		return pg
+	case p.slots <- struct{}{}:
+		pg, err := startPostgres(context.Background(), postgresConfig{
+			image:    defaultPostgresImage,
+			testName: sharedTestName,
+		})
+		if err != nil {
+			<-p.slots
+			t.Fatalf("Failed to set up pooled PostgreSQL container: %v", err)
+		}
+		registerShared(pg, pg.terminate)
+		return pg
+	}
+}
+
+// checkin truncates a container's tables and makes it available again. A
+// container that cannot be cleaned is discarded so it can't leak state.
+func (p *containerPool) checkin(pg *PostgresContainer) {
+	if err := truncateAllTables(pg.DB, nil); err != nil {
+		unregisterShared(pg)
+		pg.terminate()
+		<-p.slots
+		return
+	}
+
+	p.idle <- pg
+}
+
+// SetupPostgresSchema creates a uniquely named schema on a shared container
+// and returns a connection whose search_path points at it. Models are
+// migrated inside the new schema, and the schema is dropped when the test
+// ends. Unlike TruncateTables this isolates parallel tests completely.
+func SetupPostgresSchema(t *testing.T, shared *PostgresContainer, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	schema := "test_" + randomSuffix()
+	if err := shared.DB.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema)).Error; err != nil {
+		t.Fatalf("Failed to create schema %s: %v", schema, err)
+	}
+
+	db, err := gorm.Open(postgres.Open(shared.DSN+" search_path="+schema), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to schema %s: %v", schema, err)
+	}
+
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+		shared.DB.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+	})
+
+	if len(models) > 0 {
+		if err := db.AutoMigrate(models...); err != nil {
+			t.Fatalf("Failed to run migrations in schema %s: %v", schema, err)
+		}
+	}
+
+	return db
+}
+
+// TerminateSharedContainers stops every shared container, such as the one
+// started by SetupSharedPostgres. Call it from TestMain once m.Run returns:
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		testing.TerminateSharedContainers()
+//		os.Exit(code)
+//	}
+//
+// Without it, shared containers are left to the testcontainers reaper, which
+// removes them shortly after the test binary exits. Afterwards the next
+// SetupSharedPostgres or SetupPostgresFromPool starts new containers.
+func TerminateSharedContainers() {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	for _, shared := range sharedTerminators {
+		shared.terminate()
+	}
+	sharedTerminators = nil
+
+	// the stopped containers must not be handed out again
+	sharedPostgresOnce = sync.Once{}
+	sharedPostgres, sharedPostgresErr = nil, nil
+	if postgresPool != nil {
+		for len(postgresPool.idle) > 0 {
+			<-postgresPool.idle
+		}
+	}
+	postgresPoolOnce = sync.Once{}
+	postgresPool = nil
+}
+
+// registerShared records a shared container for TerminateSharedContainers
+func registerShared(key any, terminate func()) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	sharedTerminators = append(sharedTerminators, sharedTerminator{key: key, terminate: terminate})
+}
+
+// unregisterShared forgets a shared container that was already terminated
+func unregisterShared(key any) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	sharedTerminators = slices.DeleteFunc(sharedTerminators, func(shared sharedTerminator) bool {
+		return shared.key == key
+	})
+}
+
+// randomSuffix returns a short random hex string for unique resource names
+func randomSuffix() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,66 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+// Snapshot dumps the current table data of the container's database to a
+// file inside the container and returns its path for Restore. It is meant to
+// pair with seeding a large baseline once per suite and restoring it between
+// tests, which is much faster than reseeding.
+//
+// Only data is captured: the schema must be the same when restoring.
+func (p *PostgresContainer) Snapshot(t *testing.T) string {
+	t.Helper()
+
+	path := fmt.Sprintf("/tmp/snapshot_%s.dump", randomSuffix())
+	err := execInContainer(context.Background(), p.Container,
+		"pg_dump", "-U", p.user, "-d", p.dbname, "--data-only", "--format=custom", "-f", path)
+	if err != nil {
+		t.Fatalf("Failed to snapshot database: %v", err)
+	}
+
+	return path
+}
+
+// Restore truncates every table and reloads the data captured by Snapshot.
+// Unlike TruncateAllTables it also clears migration bookkeeping tables, whose
+// rows are part of the snapshot.
+func (p *PostgresContainer) Restore(t *testing.T, snapshot string) {
+	t.Helper()
+
+	tables, err := listTables(p.DB)
+	if err != nil {
+		t.Fatalf("Failed to list tables: %v", err)
+	}
+	if err := truncateTables(p.DB, tables); err != nil {
+		t.Fatalf("Failed to truncate tables: %v", err)
+	}
+
+	err = execInContainer(context.Background(), p.Container,
+		"pg_restore", "-U", p.user, "-d", p.dbname, "--data-only", "--disable-triggers", "--single-transaction", snapshot)
+	if err != nil {
+		t.Fatalf("Failed to restore snapshot %s: %v", snapshot, err)
+	}
+}
+
+// execInContainer runs a command in the container and returns its output as
+// an error if it exits with a non-zero code
+func execInContainer(ctx context.Context, container testcontainers.Container, cmd ...string) error {
+	code, out, err := container.Exec(ctx, cmd, tcexec.Multiplexed())
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		output, _ := io.ReadAll(out)
+		return fmt.Errorf("%s exited with code %d: %s", cmd[0], code, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package testing
+
+import (
+	"fmt"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// SetupSQLite opens an in-memory SQLite database, migrates the given models
+// and closes it when the test ends. Each call gets its own database, shared
+// across the pool's connections, so it is fast enough to use by default and
+// safe for parallel tests. The driver needs cgo.
+//
+// SQLite is not Postgres. Keep tests that depend on the dialect on a real
+// container, e.g. via ForEachDB, because SQLite:
+//   - stores any value in any column, so type and length errors go unnoticed
+//   - has no schemas, sequences, arrays, JSONB, ILIKE or SELECT ... FOR UPDATE
+//   - matches LIKE case-insensitively for ASCII
+//   - locks the whole database per write, so concurrent writers may fail
+//     with "database table is locked" instead of blocking
+func SetupSQLite(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	// a named shared-cache database lives as long as one connection is open,
+	// unlike ":memory:" which gives every pool connection an empty database
+	dsn := fmt.Sprintf("file:test_%s?mode=memory&cache=shared&_foreign_keys=1", randomSuffix())
+	// sqlite3 errors carry no SQLSTATE, so let gorm map constraint failures
+	// to the errors AssertUniqueViolation and AssertForeignKeyViolation accept
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	if len(models) > 0 {
+		if err := db.AutoMigrate(models...); err != nil {
+			t.Fatalf("Failed to run SQLite migrations: %v", err)
+		}
+	}
+
+	return db
+}
@@ -0,0 +1,38 @@
+package testing
+
+import "testing"
+
+// tableTestConfig holds the settings applied by TableTestOption values
+type tableTestConfig struct {
+	parallel bool
+}
+
+// TableTestOption configures RunTableTests
+type TableTestOption func(*tableTestConfig)
+
+// WithParallel runs every case with t.Parallel. Cases must not share
+// mutable state, including a database without per-test isolation.
+func WithParallel() TableTestOption {
+	return func(c *tableTestConfig) {
+		c.parallel = true
+	}
+}
+
+// RunTableTests runs fn as a subtest for each case, named by name(c)
+func RunTableTests[C any](t *testing.T, cases []C, name func(C) string, fn func(t *testing.T, c C), opts ...TableTestOption) {
+	t.Helper()
+
+	var cfg tableTestConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	for _, c := range cases {
+		t.Run(name(c), func(t *testing.T) {
+			if cfg.parallel {
+				t.Parallel()
+			}
+			fn(t, c)
+		})
+	}
+}
@@ -3,15 +3,28 @@ package testing
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/redis/go-redis/v9"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // PostgresContainer wraps a PostgreSQL test container
@@ -19,64 +32,403 @@ type PostgresContainer struct {
 	Container testcontainers.Container
 	DB        *gorm.DB
 	DSN       string
+	// user and dbname are the credentials the container was created with,
+	// for commands run inside it such as pg_dump
+	user   string
+	dbname string
+}
+
+const defaultPostgresImage = "postgres:16-alpine"
+
+// Default credentials of the PostgreSQL and MySQL containers, see
+// WithCredentials and WithMySQLCredentials
+const (
+	defaultDBUser     = "test"
+	defaultDBPassword = "test"
+	defaultDBName     = "testdb"
+)
+
+// postgresConfig holds the settings applied by PostgresOption values
+type postgresConfig struct {
+	image         string
+	migrations    []interface{}
+	seedFiles     []string
+	initScripts   []string
+	logsOnFailure bool
+	waitStrategy  wait.Strategy
+	poolSettings  []func(*sql.DB)
+	gormLogger    logger.Interface
+	env           map[string]string
+	command       []string
+	network       networkConfig
+	tmpfs         bool
+	user          string
+	password      string
+	dbname        string
+	healthProbe   func(ctx context.Context, db *sql.DB) error
+	// testName labels the container, see containerLabels
+	testName string
+}
+
+// PostgresOption configures SetupPostgres
+type PostgresOption func(*postgresConfig)
+
+// WithImage overrides the PostgreSQL image (default postgres:16-alpine)
+func WithImage(image string) PostgresOption {
+	return func(c *postgresConfig) {
+		c.image = image
+	}
+}
+
+// WithMigrations runs gorm AutoMigrate on the given models once the
+// database is reachable
+func WithMigrations(models ...interface{}) PostgresOption {
+	return func(c *postgresConfig) {
+		c.migrations = append(c.migrations, models...)
+	}
+}
+
+// WithSeedFiles executes the given .sql files (or glob patterns) after
+// migrations, see SeedSQL
+func WithSeedFiles(paths ...string) PostgresOption {
+	return func(c *postgresConfig) {
+		c.seedFiles = append(c.seedFiles, paths...)
+	}
+}
+
+// WithInitScripts mounts local .sql files into /docker-entrypoint-initdb.d/
+// so PostgreSQL runs them, in the given order, when the database is first
+// created. Use it for extensions (CREATE EXTENSION pgcrypto) that must exist
+// before migrations run.
+func WithInitScripts(paths ...string) PostgresOption {
+	return func(c *postgresConfig) {
+		c.initScripts = append(c.initScripts, paths...)
+	}
+}
+
+// WithLogsOnFailure writes the container's logs to the test output when the
+// test fails
+func WithLogsOnFailure() PostgresOption {
+	return func(c *postgresConfig) {
+		c.logsOnFailure = true
+	}
+}
+
+// WithWaitStrategy replaces the default readiness check, which waits for the
+// second "ready to accept connections" log line. Use it for images whose
+// entrypoint logs differently, e.g. wait.ForSQL("5432/tcp", "pgx", ...) to
+// poll until a query succeeds.
+func WithWaitStrategy(strategy wait.Strategy) PostgresOption {
+	return func(c *postgresConfig) {
+		c.waitStrategy = strategy
+	}
+}
+
+// WithHealthProbe replaces the default ping run once the wait strategy has
+// passed, e.g. to query a table an init script creates. It is retried for
+// up to 10s, see WaitForHealthy.
+func WithHealthProbe(probe func(ctx context.Context, db *sql.DB) error) PostgresOption {
+	return func(c *postgresConfig) {
+		c.healthProbe = probe
+	}
+}
+
+// WithMaxOpenConns limits the number of open connections, see
+// sql.DB.SetMaxOpenConns. A small limit reproduces pool exhaustion.
+func WithMaxOpenConns(n int) PostgresOption {
+	return func(c *postgresConfig) {
+		c.poolSettings = append(c.poolSettings, func(db *sql.DB) { db.SetMaxOpenConns(n) })
+	}
+}
+
+// WithMaxIdleConns limits the number of idle connections, see
+// sql.DB.SetMaxIdleConns
+func WithMaxIdleConns(n int) PostgresOption {
+	return func(c *postgresConfig) {
+		c.poolSettings = append(c.poolSettings, func(db *sql.DB) { db.SetMaxIdleConns(n) })
+	}
+}
+
+// WithConnMaxLifetime limits how long a connection may be reused, see
+// sql.DB.SetConnMaxLifetime
+func WithConnMaxLifetime(d time.Duration) PostgresOption {
+	return func(c *postgresConfig) {
+		c.poolSettings = append(c.poolSettings, func(db *sql.DB) { db.SetConnMaxLifetime(d) })
+	}
+}
+
+// WithGormLogger sets the logger of the returned DB, e.g.
+// logger.Default.LogMode(logger.Info) to print every statement
+func WithGormLogger(l logger.Interface) PostgresOption {
+	return func(c *postgresConfig) {
+		c.gormLogger = l
+	}
+}
+
+// WithEnv sets an environment variable on the container, e.g.
+// POSTGRES_INITDB_ARGS or LANG. It may be repeated; later values win.
+// Overriding POSTGRES_USER, POSTGRES_PASSWORD or POSTGRES_DB breaks the
+// generated DSN; use WithCredentials instead.
+func WithEnv(key, value string) PostgresOption {
+	return func(c *postgresConfig) {
+		if c.env == nil {
+			c.env = make(map[string]string)
+		}
+		c.env[key] = value
+	}
+}
+
+// WithCommand passes server flags to postgres, e.g.
+// WithCommand("-c", "max_connections=500", "-c", "shared_buffers=256MB").
+// Repeated calls append.
+func WithCommand(args ...string) PostgresOption {
+	return func(c *postgresConfig) {
+		c.command = append(c.command, args...)
+	}
+}
+
+// WithCredentials replaces the default user, password and database
+// (test/test/testdb). The user is created as the superuser, and DSN, Snapshot
+// and ReadOnlyDB all use these values.
+func WithCredentials(user, password, dbname string) PostgresOption {
+	return func(c *postgresConfig) {
+		c.user = user
+		c.password = password
+		c.dbname = dbname
+	}
+}
+
+// WithNetwork joins the container to nw, created by CreateNetwork, where
+// other containers reach it as alias:5432
+func WithNetwork(nw *testcontainers.DockerNetwork, alias string) PostgresOption {
+	return func(c *postgresConfig) {
+		c.network = networkConfig{name: nw.Name, aliases: []string{alias}}
+	}
+}
+
+// postgresTmpfsEnv, when set to 1, enables WithTmpfs for every PostgreSQL
+// container, including shared and pooled ones
+const postgresTmpfsEnv = "TEST_POSTGRES_TMPFS"
+
+// postgresDataDir is where PGDATA points when the data directory is on tmpfs
+const postgresDataDir = "/var/lib/postgresql/data"
+
+// WithTmpfs keeps the data directory on a tmpfs, so writes and fsyncs never
+// touch the container's overlay filesystem. I/O-bound suites typically run
+// 2-3x faster; the cost is RAM equal to the data directory, about 40MB for a
+// fresh cluster plus whatever the tests insert. Data is lost when the
+// container stops, which tests never rely on. Setting TEST_POSTGRES_TMPFS=1
+// enables it for every container without changing code.
+func WithTmpfs() PostgresOption {
+	return func(c *postgresConfig) {
+		c.tmpfs = true
+	}
 }
 
 // SetupPostgres creates a PostgreSQL test container
-func SetupPostgres(t *testing.T) *PostgresContainer {
+func SetupPostgres(t *testing.T, opts ...PostgresOption) *PostgresContainer {
 	t.Helper()
 
-	ctx := context.Background()
+	return SetupPostgresCtx(context.Background(), t, opts...)
+}
+
+// SetupPostgresCtx is SetupPostgres with a caller-supplied context. Container
+// startup, the host/port lookups, connecting, migrations and seeding all stop
+// when ctx is done; cleanup still terminates the container afterwards.
+func SetupPostgresCtx(ctx context.Context, t *testing.T, opts ...PostgresOption) *PostgresContainer {
+	t.Helper()
+
+	cfg := postgresConfig{
+		image:    defaultPostgresImage,
+		testName: t.Name(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	pg, err := startPostgres(ctx, cfg)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	t.Cleanup(pg.terminate)
+	if cfg.logsOnFailure {
+		dumpLogsOnFailure(t, pg.Container)
+	}
+
+	return pg
+}
+
+// startPostgres starts a PostgreSQL container and connects to it. The caller
+// is responsible for calling terminate on the result.
+func startPostgres(ctx context.Context, cfg postgresConfig) (*PostgresContainer, error) {
+	seedFiles, err := expandSQLPaths(cfg.seedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	var initFiles []testcontainers.ContainerFile
+	for i, path := range cfg.initScripts {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("Init script %s is not readable: %w", path, err)
+		}
+		// The entrypoint runs scripts in name order; keep the caller's order
+		initFiles = append(initFiles, testcontainers.ContainerFile{
+			HostFilePath:      path,
+			ContainerFilePath: fmt.Sprintf("/docker-entrypoint-initdb.d/%02d_%s", i, filepath.Base(path)),
+			FileMode:          0o644,
+		})
+	}
+
+	waitStrategy := cfg.waitStrategy
+	if waitStrategy == nil {
+		// The entrypoint logs the line once for the temporary init server
+		// and once for the real one
+		waitStrategy = wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).
+			WithStartupTimeout(60 * time.Second)
+	}
+
+	user, password, dbname := defaultDBUser, defaultDBPassword, defaultDBName
+	if cfg.user != "" {
+		user, password, dbname = cfg.user, cfg.password, cfg.dbname
+	}
+
+	env := map[string]string{
+		"POSTGRES_USER":     user,
+		"POSTGRES_PASSWORD": password,
+		"POSTGRES_DB":       dbname,
+	}
+	tmpfs := cfg.tmpfs || os.Getenv(postgresTmpfsEnv) == "1"
+	if tmpfs {
+		// newer images default PGDATA elsewhere; pin it to the mount
+		env["PGDATA"] = postgresDataDir
+	}
+	maps.Copy(env, cfg.env)
 
 	req := testcontainers.ContainerRequest{
-		Image:        "postgres:16-alpine",
+		Image:        cfg.image,
+		Name:         containerName(cfg.testName),
+		Labels:       containerLabels(cfg.testName),
 		ExposedPorts: []string{"5432/tcp"},
-		Env: map[string]string{
-			"POSTGRES_USER":     "test",
-			"POSTGRES_PASSWORD": "test",
-			"POSTGRES_DB":       "testdb",
-		},
-		WaitingFor: wait.ForLog("database system is ready to accept connections").
-			WithOccurrence(2).
-			WithStartupTimeout(60 * time.Second),
+		Env:          env,
+		Cmd:          cfg.command,
+		Files:        initFiles,
+		WaitingFor:   waitStrategy,
+	}
+	if tmpfs {
+		req.Tmpfs = map[string]string{postgresDataDir: "rw"}
 	}
+	cfg.network.apply(&req)
 
 	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: req,
 		Started:          true,
 	})
 	if err != nil {
-		t.Fatalf("Failed to start PostgreSQL container: %v", err)
+		return nil, fmt.Errorf("Failed to start PostgreSQL container: %w", err)
 	}
 
+	pg := &PostgresContainer{Container: container, user: user, dbname: dbname}
+
 	host, err := container.Host(ctx)
 	if err != nil {
-		t.Fatalf("Failed to get container host: %v", err)
+		pg.terminate()
+		return nil, fmt.Errorf("Failed to get container host: %w", err)
 	}
 
 	port, err := container.MappedPort(ctx, "5432")
 	if err != nil {
-		t.Fatalf("Failed to get container port: %v", err)
+		pg.terminate()
+		return nil, fmt.Errorf("Failed to get container port: %w", err)
 	}
 
-	dsn := fmt.Sprintf("host=%s port=%s user=test password=test dbname=testdb sslmode=disable",
-		host, port.Port())
+	pg.DSN = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		host, port.Port(), dsnValue(user), dsnValue(password), dsnValue(dbname))
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	pg.DB, err = gorm.Open(postgres.Open(pg.DSN), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               cfg.gormLogger,
+	})
 	if err != nil {
-		t.Fatalf("Failed to connect to database: %v", err)
+		pg.terminate()
+		return nil, fmt.Errorf("Failed to connect to database: %w", err)
+	}
+
+	sqlDB, err := pg.DB.DB()
+	if err != nil {
+		pg.terminate()
+		return nil, fmt.Errorf("Failed to get database handle: %w", err)
+	}
+	for _, apply := range cfg.poolSettings {
+		apply(sqlDB)
+	}
+	probe := cfg.healthProbe
+	if probe == nil {
+		probe = func(ctx context.Context, db *sql.DB) error {
+			return db.PingContext(ctx)
+		}
+	}
+	err = waitForHealthy(ctx, container, connectTimeout, func(ctx context.Context) error {
+		return probe(ctx, sqlDB)
+	})
+	if err != nil {
+		pg.terminate()
+		return nil, fmt.Errorf("Failed to ping database: %w", err)
+	}
+
+	if len(cfg.migrations) > 0 {
+		if err := pg.DB.WithContext(ctx).AutoMigrate(cfg.migrations...); err != nil {
+			pg.terminate()
+			return nil, fmt.Errorf("Failed to run migrations: %w", err)
+		}
+	}
+
+	if err := execSQLFiles(pg.DB.WithContext(ctx), seedFiles); err != nil {
+		pg.terminate()
+		return nil, err
+	}
+
+	return pg, nil
+}
+
+// terminate closes the database connection and stops the container
+func (p *PostgresContainer) terminate() {
+	if p.DB != nil {
+		if sqlDB, err := p.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	p.Container.Terminate(context.Background())
+}
+
+// dsnValue quotes a keyword/value DSN value when it is empty or contains
+// spaces, quotes or backslashes
+func dsnValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// ReadOnlyDB opens a second connection to the container with
+// default_transaction_read_only=on, so writes through it fail. Use it with
+// DB to test code that routes reads and writes to separate handles. The
+// connection is closed when the test ends.
+func (p *PostgresContainer) ReadOnlyDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open(p.DSN+" default_transaction_read_only=on"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open read-only connection: %v", err)
 	}
 
 	t.Cleanup(func() {
 		sqlDB, _ := db.DB()
 		sqlDB.Close()
-		container.Terminate(ctx)
 	})
 
-	return &PostgresContainer{
-		Container: container,
-		DB:        db,
-		DSN:       dsn,
-	}
+	return db
 }
 
 // RedisContainer wraps a Redis test container
@@ -86,17 +438,96 @@ type RedisContainer struct {
 	Addr      string
 }
 
+const (
+	defaultRedisImage   = "redis:7-alpine"
+	defaultRedisWaitLog = "Ready to accept connections"
+)
+
+// redisConfig holds the settings applied by RedisOption values
+type redisConfig struct {
+	image         string
+	waitLog       string
+	clientOptions []func(*redis.Options)
+	logsOnFailure bool
+	network       networkConfig
+}
+
+// RedisOption configures SetupRedis
+type RedisOption func(*redisConfig)
+
+// WithRedisImage overrides the Redis image (default redis:7-alpine), e.g.
+// redis/redis-stack-server:latest for RediSearch and RedisJSON
+func WithRedisImage(image string) RedisOption {
+	return func(c *redisConfig) {
+		c.image = image
+	}
+}
+
+// WithRedisWaitLog overrides the log line that signals Redis is ready
+func WithRedisWaitLog(log string) RedisOption {
+	return func(c *redisConfig) {
+		c.waitLog = log
+	}
+}
+
+// WithRedisClientOptions adjusts the client options, e.g. DB or Password,
+// before the client is created. Addr is always set to the container.
+func WithRedisClientOptions(fn func(*redis.Options)) RedisOption {
+	return func(c *redisConfig) {
+		c.clientOptions = append(c.clientOptions, fn)
+	}
+}
+
+// WithRedisLogsOnFailure writes the container's logs to the test output when
+// the test fails
+func WithRedisLogsOnFailure() RedisOption {
+	return func(c *redisConfig) {
+		c.logsOnFailure = true
+	}
+}
+
+// WithRedisNetwork joins the container to nw, created by CreateNetwork, where
+// other containers reach it as alias:6379
+func WithRedisNetwork(nw *testcontainers.DockerNetwork, alias string) RedisOption {
+	return func(c *redisConfig) {
+		c.network = networkConfig{name: nw.Name, aliases: []string{alias}}
+	}
+}
+
+// newRedisConfig applies opts over the default Redis settings
+func newRedisConfig(opts []RedisOption) redisConfig {
+	cfg := redisConfig{
+		image:   defaultRedisImage,
+		waitLog: defaultRedisWaitLog,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
 // SetupRedis creates a Redis test container
-func SetupRedis(t *testing.T) *RedisContainer {
+func SetupRedis(t *testing.T, opts ...RedisOption) *RedisContainer {
 	t.Helper()
 
-	ctx := context.Background()
+	return SetupRedisCtx(context.Background(), t, opts...)
+}
+
+// SetupRedisCtx is SetupRedis with a caller-supplied context bounding
+// container startup and the initial ping
+func SetupRedisCtx(ctx context.Context, t *testing.T, opts ...RedisOption) *RedisContainer {
+	t.Helper()
+
+	cfg := newRedisConfig(opts)
 
 	req := testcontainers.ContainerRequest{
-		Image:        "redis:7-alpine",
+		Image:        cfg.image,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
 		ExposedPorts: []string{"6379/tcp"},
-		WaitingFor:   wait.ForLog("Ready to accept connections"),
+		WaitingFor:   wait.ForLog(cfg.waitLog),
 	}
+	cfg.network.apply(&req)
 
 	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: req,
@@ -106,6 +537,13 @@ func SetupRedis(t *testing.T) *RedisContainer {
 		t.Fatalf("Failed to start Redis container: %v", err)
 	}
 
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+	if cfg.logsOnFailure {
+		dumpLogsOnFailure(t, container)
+	}
+
 	host, err := container.Host(ctx)
 	if err != nil {
 		t.Fatalf("Failed to get container host: %v", err)
@@ -117,20 +555,24 @@ func SetupRedis(t *testing.T) *RedisContainer {
 	}
 
 	addr := fmt.Sprintf("%s:%s", host, port.Port())
-	client := redis.NewClient(&redis.Options{
-		Addr: addr,
+	clientOpts := &redis.Options{}
+	for _, fn := range cfg.clientOptions {
+		fn(clientOpts)
+	}
+	clientOpts.Addr = addr
+	client := redis.NewClient(clientOpts)
+	t.Cleanup(func() {
+		client.Close()
 	})
 
 	// Test connection
-	if err := client.Ping(ctx).Err(); err != nil {
+	err = waitForHealthy(ctx, container, connectTimeout, func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+	if err != nil {
 		t.Fatalf("Failed to connect to Redis: %v", err)
 	}
 
-	t.Cleanup(func() {
-		client.Close()
-		container.Terminate(ctx)
-	})
-
 	return &RedisContainer{
 		Container: container,
 		Client:    client,
@@ -149,6 +591,79 @@ func TruncateTables(t *testing.T, db *gorm.DB, tables ...string) {
 	}
 }
 
+// TruncateTablesRestartIdentity truncates tables and resets their identity
+// sequences so auto-increment IDs start from 1 again
+func TruncateTablesRestartIdentity(t *testing.T, db *gorm.DB, tables ...string) {
+	t.Helper()
+
+	for _, table := range tables {
+		if err := db.Exec(fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY CASCADE", table)).Error; err != nil {
+			t.Fatalf("Failed to truncate table %s: %v", table, err)
+		}
+	}
+}
+
+// defaultTruncateExclusions are migration bookkeeping tables that
+// TruncateAllTables never touches
+var defaultTruncateExclusions = []string{"schema_migrations", migrationsTable}
+
+// TruncateAllTables truncates every table in the current schema (public by
+// default) in a single statement, restarting identity sequences. Tables named
+// in exclude, such as seeded lookup tables, are preserved, as are migration
+// bookkeeping tables.
+func TruncateAllTables(t *testing.T, db *gorm.DB, exclude ...string) {
+	t.Helper()
+
+	if err := truncateAllTables(db, exclude); err != nil {
+		t.Fatalf("Failed to truncate tables: %v", err)
+	}
+}
+
+// truncateAllTables truncates every table in the current schema except the
+// excluded and migration bookkeeping tables
+func truncateAllTables(db *gorm.DB, exclude []string) error {
+	tables, err := listTables(db)
+	if err != nil {
+		return err
+	}
+
+	var targets []string
+	for _, table := range tables {
+		if !slices.Contains(exclude, table) && !slices.Contains(defaultTruncateExclusions, table) {
+			targets = append(targets, table)
+		}
+	}
+	return truncateTables(db, targets)
+}
+
+// truncateTables truncates the given tables in a single statement
+func truncateTables(db *gorm.DB, tables []string) error {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	return db.Exec(fmt.Sprintf("TRUNCATE TABLE %s RESTART IDENTITY CASCADE", quoteIdents(tables))).Error
+}
+
+// listTables returns the base tables in the connection's current schema
+func listTables(db *gorm.DB) ([]string, error) {
+	var tables []string
+	err := db.Raw(`SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'
+		ORDER BY table_name`).
+		Scan(&tables).Error
+	return tables, err
+}
+
+// quoteIdents quotes table names and joins them for use in a SQL statement
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, ", ")
+}
+
 // FlushRedis flushes all Redis data
 func FlushRedis(t *testing.T, client *redis.Client) {
 	t.Helper()
@@ -158,11 +673,117 @@ func FlushRedis(t *testing.T, client *redis.Client) {
 	}
 }
 
+// flushScanCount is the SCAN batch size hint used by FlushRedisPattern
+const flushScanCount = 100
+
+// FlushRedisPattern deletes only the keys matching a glob-style pattern such
+// as "test:orders:*", so parallel tests sharing one Redis can each clear their
+// own namespace. It walks the keyspace with SCAN rather than KEYS, so Redis is
+// never blocked on a large keyspace.
+func FlushRedisPattern(t *testing.T, client *redis.Client, pattern string) {
+	t.Helper()
+
+	ctx := context.Background()
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, flushScanCount).Result()
+		if err != nil {
+			t.Fatalf("Failed to scan Redis keys matching %s: %v", pattern, err)
+		}
+		if len(keys) > 0 {
+			if err := client.Del(ctx, keys...).Err(); err != nil {
+				t.Fatalf("Failed to delete Redis keys matching %s: %v", pattern, err)
+			}
+		}
+		if next == 0 {
+			return
+		}
+		cursor = next
+	}
+}
+
+// SeedRedis sets each key to its value. A ttl of 0 means the keys do not
+// expire.
+func SeedRedis(t *testing.T, client *redis.Client, data map[string]string, ttl time.Duration) {
+	t.Helper()
+
+	ctx := context.Background()
+	for key, value := range data {
+		if err := client.Set(ctx, key, value, ttl).Err(); err != nil {
+			t.Fatalf("Failed to seed Redis key %s: %v", key, err)
+		}
+	}
+}
+
+// SeedRedisHashes stores each map as a hash under its key. A ttl of 0 means
+// the keys do not expire.
+func SeedRedisHashes(t *testing.T, client *redis.Client, hashes map[string]map[string]string, ttl time.Duration) {
+	t.Helper()
+
+	ctx := context.Background()
+	for key, fields := range hashes {
+		if err := client.HSet(ctx, key, fields).Err(); err != nil {
+			t.Fatalf("Failed to seed Redis hash %s: %v", key, err)
+		}
+		if ttl > 0 {
+			if err := client.Expire(ctx, key, ttl).Err(); err != nil {
+				t.Fatalf("Failed to set TTL on Redis hash %s: %v", key, err)
+			}
+		}
+	}
+}
+
 // RunInTransaction runs a function in a database transaction and rolls back
 func RunInTransaction(t *testing.T, db *gorm.DB, fn func(tx *gorm.DB)) {
 	t.Helper()
 
 	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("Failed to begin transaction: %v", tx.Error)
+	}
+	defer tx.Rollback()
+
+	fn(tx)
+}
+
+// RunInTransactionE runs a function in a database transaction, rolls back and
+// returns the function's error so tests can assert on expected failures
+func RunInTransactionE(t *testing.T, db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	t.Helper()
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("Failed to begin transaction: %v", tx.Error)
+	}
+	defer tx.Rollback()
+
+	return fn(tx)
+}
+
+// TxTest runs fn inside a transaction that is always rolled back, isolating
+// tests without truncation even when the code under test uses transactions
+// itself. Pass tx to that code in place of the original db:
+//
+//   - tx.Transaction(func(...) error) opens a SAVEPOINT; returning nil
+//     releases it, and returning an error or panicking rolls back to it, so
+//     the service's own commit/rollback paths really run
+//   - tx.SavePoint(name) / tx.RollbackTo(name) can be used directly to assert
+//     on partial rollbacks
+//   - calling tx.Commit() ends the outer transaction and breaks isolation, so
+//     code that commits explicitly is not supported
+//
+// db must not have DisableNestedTransaction set, since nesting relies on it.
+func TxTest(t *testing.T, db *gorm.DB, fn func(tx *gorm.DB)) {
+	t.Helper()
+
+	if db.DisableNestedTransaction {
+		t.Fatalf("TxTest requires savepoint-based nested transactions, but DisableNestedTransaction is set")
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("Failed to begin transaction: %v", tx.Error)
+	}
 	defer tx.Rollback()
 
 	fn(tx)
@@ -172,7 +793,16 @@ func RunInTransaction(t *testing.T, db *gorm.DB, fn func(tx *gorm.DB)) {
 func AssertNoError(t *testing.T, err error) {
 	t.Helper()
 	if err != nil {
-		t.Fatalf("Unexpected error: %v", err)
+		failf(t, "AssertNoError", err, nil, "Unexpected error: %v", err)
+	}
+}
+
+// AssertNoErrorf is AssertNoError with a formatted message appended to the
+// failure output
+func AssertNoErrorf(t *testing.T, err error, format string, args ...any) {
+	t.Helper()
+	if err != nil {
+		failf(t, "AssertNoErrorf", err, nil, "Unexpected error: %v: %s", err, fmt.Sprintf(format, args...))
 	}
 }
 
@@ -180,15 +810,82 @@ func AssertNoError(t *testing.T, err error) {
 func AssertError(t *testing.T, err error) {
 	t.Helper()
 	if err == nil {
-		t.Fatal("Expected an error but got nil")
+		failf(t, "AssertError", nil, "error", "Expected an error but got nil")
+	}
+}
+
+// AssertErrorIs is a helper to assert err matches target via errors.Is
+func AssertErrorIs(t *testing.T, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		failf(t, "AssertErrorIs", err, target, "Expected error matching %v, got chain:%s", target, errorChain(err))
+	}
+}
+
+// AssertErrorContains is a helper to assert err is non-nil and its message
+// contains substr, for errors without a sentinel value to match
+func AssertErrorContains(t *testing.T, err error, substr string) {
+	t.Helper()
+	switch {
+	case err == nil:
+		failf(t, "AssertErrorContains", nil, substr, "Expected an error containing %q, got nil", substr)
+	case !strings.Contains(err.Error(), substr):
+		failf(t, "AssertErrorContains", err, substr, "Expected error containing %q, got %q", substr, err.Error())
+	}
+}
+
+// AssertErrorAs is a helper to assert err has a T in its chain via errors.As.
+// It returns the matched error for further assertions. T must be an interface
+// or a type implementing error.
+func AssertErrorAs[T any](t *testing.T, err error) T {
+	t.Helper()
+	var target T
+	if !errors.As(err, &target) {
+		failf(t, "AssertErrorAs", err, fmt.Sprintf("%T", target), "Expected error of type %T, got chain:%s", target, errorChain(err))
+	}
+	return target
+}
+
+// errorChain formats each error in err's Unwrap chain on its own line
+func errorChain(err error) string {
+	if err == nil {
+		return " <nil>"
 	}
+
+	var b strings.Builder
+	for i := 0; err != nil; i++ {
+		fmt.Fprintf(&b, "\n\t%d: %T: %v", i, err, err)
+		err = errors.Unwrap(err)
+	}
+	return b.String()
 }
 
 // AssertEqual is a helper to assert two values are equal
 func AssertEqual[T comparable](t *testing.T, got, want T) {
 	t.Helper()
 	if got != want {
-		t.Fatalf("Got %v, want %v", got, want)
+		failf(t, "AssertEqual", got, want, "Got %v, want %v", got, want)
+	}
+}
+
+// AssertEqualf is AssertEqual with a formatted message appended to the
+// failure output, e.g. the loop index
+func AssertEqualf[T comparable](t *testing.T, got, want T, format string, args ...any) {
+	t.Helper()
+	if got != want {
+		failf(t, "AssertEqualf", got, want, "Got %v, want %v: %s", got, want, fmt.Sprintf(format, args...))
+	}
+}
+
+// AssertDeepEqual is a helper to assert two values are deeply equal. Unlike
+// AssertEqual it works for non-comparable types such as slices, maps and
+// structs containing them, and prints a diff on failure.
+func AssertDeepEqual[T any](t *testing.T, got, want T) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		// Exporter lets cmp look at unexported fields instead of panicking
+		exportAll := cmp.Exporter(func(reflect.Type) bool { return true })
+		failf(t, "AssertDeepEqual", got, want, "Values differ (-want +got):\n%s", cmp.Diff(want, got, exportAll))
 	}
 }
 
@@ -196,15 +893,54 @@ func AssertEqual[T comparable](t *testing.T, got, want T) {
 func AssertNotEqual[T comparable](t *testing.T, got, want T) {
 	t.Helper()
 	if got == want {
-		t.Fatalf("Got %v, want not equal", got)
+		failf(t, "AssertNotEqual", got, nil, "Got %v, want not equal", got)
 	}
 }
 
+// AssertNil is a helper to assert a value is nil, including typed nil
+// pointers, slices, maps, channels, funcs and interfaces
+func AssertNil(t *testing.T, v any) {
+	t.Helper()
+	if !isNil(v) {
+		failf(t, "AssertNil", v, nil, "Expected nil, got %T: %v", v, v)
+	}
+}
+
+// AssertNotNil is a helper to assert a value is not nil, treating typed nils as nil
+func AssertNotNil(t *testing.T, v any) {
+	t.Helper()
+	if isNil(v) {
+		failf(t, "AssertNotNil", v, nil, "Expected non-nil value, got %T: %v", v, v)
+	}
+}
+
+// isNil reports whether v is nil or an interface holding a nil value
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
+
 // AssertTrue is a helper to assert a condition is true
 func AssertTrue(t *testing.T, condition bool, message string) {
 	t.Helper()
 	if !condition {
-		t.Fatalf("Assertion failed: %s", message)
+		failf(t, "AssertTrue", nil, nil, "Assertion failed: %s", message)
+	}
+}
+
+// AssertTruef is AssertTrue with a formatted message
+func AssertTruef(t *testing.T, condition bool, format string, args ...any) {
+	t.Helper()
+	if !condition {
+		failf(t, "AssertTruef", nil, nil, "Assertion failed: %s", fmt.Sprintf(format, args...))
 	}
 }
 
@@ -212,21 +948,254 @@ func AssertTrue(t *testing.T, condition bool, message string) {
 func AssertFalse(t *testing.T, condition bool, message string) {
 	t.Helper()
 	if condition {
-		t.Fatalf("Assertion failed: %s", message)
+		failf(t, "AssertFalse", nil, nil, "Assertion failed: %s", message)
 	}
 }
 
+const defaultPollInterval = 100 * time.Millisecond
+
+// WaitOptions controls how WaitForWithOptions polls its condition
+type WaitOptions struct {
+	// Interval is the delay between attempts (default 100ms)
+	Interval time.Duration
+	// Multiplier grows the interval after every attempt for exponential
+	// backoff; values <= 1 keep the interval constant
+	Multiplier float64
+	// MaxInterval caps the interval when backing off (0 means no cap)
+	MaxInterval time.Duration
+}
+
 // WaitFor waits for a condition to be true with timeout
 func WaitFor(t *testing.T, timeout time.Duration, condition func() bool) {
 	t.Helper()
 
-	deadline := time.Now().Add(timeout)
+	WaitForWithOptions(t, timeout, WaitOptions{Interval: defaultPollInterval}, condition)
+}
+
+// WaitForWithInterval waits for a condition to be true, checking it every interval
+func WaitForWithInterval(t *testing.T, timeout, interval time.Duration, condition func() bool) {
+	t.Helper()
+
+	WaitForWithOptions(t, timeout, WaitOptions{Interval: interval}, condition)
+}
+
+// WaitForWithOptions waits for a condition to be true, polling as described by opts
+func WaitForWithOptions(t *testing.T, timeout time.Duration, opts WaitOptions, condition func() bool) {
+	t.Helper()
+
+	attempts, elapsed, ok := poll(timeout, opts, condition)
+	if !ok {
+		failf(t, "WaitFor", nil, nil, "Timeout waiting for condition after %d attempts (%s elapsed)",
+			attempts, elapsed.Round(time.Millisecond))
+	}
+}
+
+// WaitForNoError waits for fn to return nil, reporting the last error on timeout
+func WaitForNoError(t *testing.T, timeout time.Duration, fn func() error) {
+	t.Helper()
+
+	var lastErr error
+	attempts, elapsed, ok := poll(timeout, WaitOptions{Interval: defaultPollInterval}, func() bool {
+		lastErr = fn()
+		return lastErr == nil
+	})
+	if !ok {
+		failf(t, "WaitForNoError", lastErr, nil, "Timeout waiting for condition after %d attempts (%s elapsed): last error: %v",
+			attempts, elapsed.Round(time.Millisecond), lastErr)
+	}
+}
+
+// AssertNeverWithin is a helper to assert condition stays false for all of d,
+// checking it every 100ms, e.g. that a deduplicated message is not delivered
+// again. The test always waits the full d when it passes.
+func AssertNeverWithin(t *testing.T, d time.Duration, condition func() bool) {
+	t.Helper()
+
+	attempts, elapsed, ok := poll(d, WaitOptions{Interval: defaultPollInterval}, condition)
+	if ok {
+		failf(t, "AssertNeverWithin", elapsed.Round(time.Millisecond), d, "Condition became true after %s (attempt %d), want false for %s",
+			elapsed.Round(time.Millisecond), attempts, d)
+	}
+}
+
+// readinessProbeTimeout bounds a single dial or request of WaitForPort and
+// WaitForHTTP so a hung attempt can't use up the whole timeout
+const readinessProbeTimeout = time.Second
+
+// WaitForPort waits until a TCP connection to addr ("host:port") succeeds,
+// e.g. for a service binary started by the test
+func WaitForPort(t *testing.T, addr string, timeout time.Duration) {
+	t.Helper()
+
+	var lastErr error
+	attempts, elapsed, ok := poll(timeout, WaitOptions{Interval: defaultPollInterval}, func() bool {
+		var conn net.Conn
+		conn, lastErr = net.DialTimeout("tcp", addr, readinessProbeTimeout)
+		if lastErr != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	})
+	if !ok {
+		failf(t, "WaitForPort", lastErr, addr, "Timeout waiting for %s to accept connections after %d attempts (%s elapsed): last error: %v",
+			addr, attempts, elapsed.Round(time.Millisecond), lastErr)
+	}
+}
+
+// WaitForHTTP waits until a GET of url returns wantStatus
+func WaitForHTTP(t *testing.T, url string, wantStatus int, timeout time.Duration) {
+	t.Helper()
+
+	client := &http.Client{Timeout: readinessProbeTimeout}
+	var lastErr error
+	attempts, elapsed, ok := poll(timeout, WaitOptions{Interval: defaultPollInterval}, func() bool {
+		resp, err := client.Get(url)
+		if err != nil {
+			lastErr = err
+			return false
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != wantStatus {
+			lastErr = fmt.Errorf("got status %q, want %d", resp.Status, wantStatus)
+			return false
+		}
+		return true
+	})
+	if !ok {
+		failf(t, "WaitForHTTP", lastErr, wantStatus, "Timeout waiting for %s after %d attempts (%s elapsed): last error: %v",
+			url, attempts, elapsed.Round(time.Millisecond), lastErr)
+	}
+}
+
+// Setup* functions retry their connect/ping step, see waitForHealthy, since a
+// port can accept connections slightly before the service behind it is ready
+const (
+	connectTimeout    = 10 * time.Second
+	connectRetryDelay = 500 * time.Millisecond
+)
+
+// Retry calls fn up to attempts times, sleeping delay between failures, and
+// fails the test with the last error if no call succeeds. Use it for setup
+// steps that can fail transiently, such as dialing a freshly started service.
+func Retry(t *testing.T, attempts int, delay time.Duration, fn func() error) {
+	t.Helper()
+
+	if attempts < 1 {
+		t.Fatalf("Retry requires at least 1 attempt, got %d", attempts)
+	}
+	if err := retry(context.Background(), attempts, delay, fn); err != nil {
+		t.Fatalf("Failed after %d attempts: %v", attempts, err)
+	}
+}
+
+// retry calls fn up to attempts times, returning nil on the first success and
+// the last error otherwise. It stops early when ctx is done.
+func retry(ctx context.Context, attempts int, delay time.Duration, fn func() error) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if i == attempts-1 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-time.After(delay):
+		}
+	}
+	return err
+}
+
+// CollectT collects assertion failures inside an AssertEventually block
+type CollectT struct {
+	errors []string
+	failed bool
+}
+
+// Errorf records a failure and lets the block continue
+func (c *CollectT) Errorf(format string, args ...any) {
+	c.errors = append(c.errors, fmt.Sprintf(format, args...))
+}
+
+// FailNow marks the attempt as failed and stops the block
+func (c *CollectT) FailNow() {
+	c.failed = true
+	runtime.Goexit()
+}
+
+// Fatalf records a failure and stops the block
+func (c *CollectT) Fatalf(format string, args ...any) {
+	c.Errorf(format, args...)
+	c.FailNow()
+}
+
+// AssertEventually runs fn until an attempt records no failures on c, retrying
+// every 100ms. If no attempt passes within timeout, the test fails with the
+// failures from the last attempt.
+func AssertEventually(t *testing.T, timeout time.Duration, fn func(c *CollectT)) {
+	t.Helper()
+
+	var last *CollectT
+	attempts, elapsed, ok := poll(timeout, WaitOptions{Interval: defaultPollInterval}, func() bool {
+		c := &CollectT{}
+		done := make(chan struct{})
+		// Run in a goroutine so FailNow can stop the block with Goexit
+		go func() {
+			defer close(done)
+			fn(c)
+		}()
+		<-done
+
+		last = c
+		return !c.failed && len(c.errors) == 0
+	})
+	if !ok {
+		var failures []string
+		if last != nil {
+			failures = last.errors
+		}
+		failf(t, "AssertEventually", nil, nil, "Condition not met after %d attempts (%s elapsed): %s",
+			attempts, elapsed.Round(time.Millisecond), strings.Join(failures, "; "))
+	}
+}
+
+// poll calls condition until it returns true or timeout passes, reporting
+// the number of attempts and the time spent
+func poll(timeout time.Duration, opts WaitOptions, condition func() bool) (int, time.Duration, bool) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+	attempts := 0
 	for time.Now().Before(deadline) {
+		attempts++
 		if condition() {
-			return
+			return attempts, time.Since(start), true
+		}
+
+		// never sleep past the deadline, however far the interval has backed off
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+		time.Sleep(min(interval, remaining))
+
+		if opts.Multiplier > 1 {
+			interval = time.Duration(float64(interval) * opts.Multiplier)
+			if opts.MaxInterval > 0 && interval > opts.MaxInterval {
+				interval = opts.MaxInterval
+			}
 		}
-		time.Sleep(100 * time.Millisecond)
 	}
 
-	t.Fatalf("Timeout waiting for condition")
+	return attempts, time.Since(start), false
 }
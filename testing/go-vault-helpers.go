@@ -0,0 +1,139 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const vaultImage = "hashicorp/vault:1.17"
+
+// vaultRootToken is the root token of the dev-mode server
+const vaultRootToken = "root"
+
+// vaultMountTimeout bounds how long EnableKVv2 waits for a new mount to
+// accept requests
+const vaultMountTimeout = 5 * time.Second
+
+// VaultContainer wraps a Vault dev-mode test container. Client is
+// authenticated with RootToken, and the dev server already has a KV v2
+// engine mounted at secret/.
+type VaultContainer struct {
+	Container testcontainers.Container
+	Client    *vault.Client
+	Address   string
+	RootToken string
+}
+
+// SetupVault creates a Vault test container running in dev mode: unsealed,
+// in-memory and with a fixed root token. Never point real secrets at it.
+func SetupVault(t *testing.T) *VaultContainer {
+	t.Helper()
+
+	return SetupVaultCtx(context.Background(), t)
+}
+
+// SetupVaultCtx is SetupVault with a caller-supplied context bounding
+// container startup
+func SetupVaultCtx(ctx context.Context, t *testing.T) *VaultContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        vaultImage,
+		Name:         containerName(t.Name()),
+		Labels:       containerLabels(t.Name()),
+		ExposedPorts: []string{"8200/tcp"},
+		Env: map[string]string{
+			"VAULT_DEV_ROOT_TOKEN_ID":  vaultRootToken,
+			"VAULT_DEV_LISTEN_ADDRESS": "0.0.0.0:8200",
+			// mlock needs IPC_LOCK, which restricted CI runners don't grant
+			"SKIP_SETCAP": "true",
+		},
+		WaitingFor: wait.ForHTTP("/v1/sys/health").
+			WithPort("8200/tcp").
+			WithStartupTimeout(60 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to start Vault container: %v", err)
+	}
+
+	t.Cleanup(func() {
+		container.Terminate(context.Background())
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get container host: %v", err)
+	}
+
+	port, err := container.MappedPort(ctx, "8200")
+	if err != nil {
+		t.Fatalf("Failed to get container port: %v", err)
+	}
+
+	address := fmt.Sprintf("http://%s:%s", host, port.Port())
+	cfg := vault.DefaultConfig()
+	cfg.Address = address
+	client, err := vault.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create Vault client: %v", err)
+	}
+	client.SetToken(vaultRootToken)
+
+	return &VaultContainer{
+		Container: container,
+		Client:    client,
+		Address:   address,
+		RootToken: vaultRootToken,
+	}
+}
+
+// EnableKVv2 mounts a KV version 2 secrets engine at path and waits until it
+// accepts requests
+func (v *VaultContainer) EnableKVv2(t *testing.T, path string) {
+	t.Helper()
+
+	err := v.Client.Sys().Mount(path, &vault.MountInput{
+		Type:    "kv",
+		Options: map[string]string{"version": "2"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to enable KV v2 at %s: %v", path, err)
+	}
+
+	// a new mount answers with an upgrade error for a moment
+	var lastErr error
+	_, _, ok := poll(vaultMountTimeout, WaitOptions{}, func() bool {
+		_, lastErr = v.Client.Logical().Read(path + "/config")
+		return lastErr == nil
+	})
+	if !ok {
+		t.Fatalf("Timeout waiting for KV v2 mount %s: %v", path, lastErr)
+	}
+}
+
+// WriteSecret stores data as a new version of the KV v2 secret at path,
+// whose first segment is the mount, e.g. "secret/myapp/db"
+func (v *VaultContainer) WriteSecret(t *testing.T, path string, data map[string]any) {
+	t.Helper()
+
+	mount, key, ok := strings.Cut(path, "/")
+	if !ok || key == "" {
+		t.Fatalf("Secret path %q must be <mount>/<key>", path)
+	}
+
+	if _, err := v.Client.KVv2(mount).Put(context.Background(), key, data); err != nil {
+		t.Fatalf("Failed to write secret %s: %v", path, err)
+	}
+}